@@ -0,0 +1,74 @@
+package medic
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultMaxInFlight is the async send limit for clients that don't set their own
+const DefaultMaxInFlight = 64
+
+// ErrTooManyInFlight is returned on an async send's channel when the client
+// already has MaxInFlight async sends running; the heartbeat is not sent
+var ErrTooManyInFlight = errors.New("too many async heartbeat sends in flight")
+
+// WithMaxInFlight caps the number of concurrent async sends
+func WithMaxInFlight(n int) Option {
+	return func(c *Client) {
+		c.MaxInFlight = n
+	}
+}
+
+// SendHeartbeatAsync sends a heartbeat on a background goroutine. See SendHeartbeatAsyncContext
+func (c *Client) SendHeartbeatAsync(h Heartbeat) <-chan error {
+	return c.SendHeartbeatAsyncContext(context.Background(), h)
+}
+
+// SendHeartbeatAsyncContext sends a heartbeat on a background goroutine and
+// returns immediately. The returned channel receives exactly one value, the
+// send's result, and is then closed. It is buffered, so callers may ignore it:
+// an unread result is dropped and the goroutine still exits.
+//
+// At most MaxInFlight sends run at once; beyond that the heartbeat is dropped
+// and ErrTooManyInFlight is delivered instead, so a flood of sends cannot
+// grow memory without bound. Cancel ctx, or Close the client, to abandon an
+// in-flight send
+func (c *Client) SendHeartbeatAsyncContext(ctx context.Context, h Heartbeat, opts ...RequestOption) <-chan error {
+	result := make(chan error, 1)
+	sem := c.inFlight()
+	select {
+	case sem <- struct{}{}:
+	default:
+		result <- ErrTooManyInFlight
+		close(result)
+		return result
+	}
+	ctx, done, err := c.trackAsync(ctx)
+	if err != nil {
+		<-sem
+		result <- err
+		close(result)
+		return result
+	}
+
+	go func() {
+		// Free the slot before closing so a caller that sees the close can send again
+		defer close(result)
+		defer func() { <-sem }()
+		defer done()
+		result <- c.SendHeartbeatContext(ctx, h, opts...)
+	}()
+	return result
+}
+
+// inFlight returns the semaphore bounding async sends, creating it on first use
+func (c *Client) inFlight() chan struct{} {
+	c.asyncOnce.Do(func() {
+		n := c.MaxInFlight
+		if n <= 0 {
+			n = DefaultMaxInFlight
+		}
+		c.asyncSem = make(chan struct{}, n)
+	})
+	return c.asyncSem
+}
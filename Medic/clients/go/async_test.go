@@ -0,0 +1,37 @@
+package medic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendHeartbeatAsync(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithMaxInFlight(1))
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	first := c.SendHeartbeatAsync(h)
+	if err := <-c.SendHeartbeatAsync(h); err != ErrTooManyInFlight {
+		t.Errorf("second SendHeartbeatAsync() error = %v, want ErrTooManyInFlight", err)
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Errorf("first SendHeartbeatAsync() unexpected error = %v", err)
+	}
+	if _, ok := <-first; ok {
+		t.Error("result channel was not closed after delivering the result")
+	}
+
+	// The slot frees up once the first send completes
+	if err := <-c.SendHeartbeatAsync(h); err != nil {
+		t.Errorf("third SendHeartbeatAsync() unexpected error = %v", err)
+	}
+}
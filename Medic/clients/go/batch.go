@@ -0,0 +1,249 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BatchError reports the heartbeats in a batch that could not be delivered
+type BatchError struct {
+	// Invalid maps batch indices to their validation errors. When set, the
+	// batch was not sent
+	Invalid map[int]error
+	// Rejected lists the names of heartbeats the server refused
+	Rejected []string
+	// Result holds the per-heartbeat outcome when the batch was sent
+	Result *BatchResult
+	// Err is the underlying request error, such as a *StatusError when the
+	// server refused the whole request
+	Err error
+}
+
+// Error summarizes the invalid indices or rejected heartbeat names
+func (e *BatchError) Error() string {
+	if len(e.Invalid) > 0 {
+		indices := make([]int, 0, len(e.Invalid))
+		for i := range e.Invalid {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+		parts := make([]string, len(indices))
+		for n, i := range indices {
+			parts[n] = fmt.Sprintf("[%d] %v", i, e.Invalid[i])
+		}
+		return fmt.Sprintf("invalid heartbeats in batch: %s", strings.Join(parts, "; "))
+	}
+	return fmt.Sprintf("heartbeats rejected by server: %s", strings.Join(e.Rejected, ", "))
+}
+
+// Unwrap returns the underlying request error, if any
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchItemResult is the server's outcome for one heartbeat in a batch
+type BatchItemResult struct {
+	// StatusCode is the per-item status reported by the server, or the status
+	// of the batch response for heartbeats it did not list as rejected
+	StatusCode int
+	// Message is the server's explanation for a rejected heartbeat
+	Message string
+	// Rejected is true when the server refused this heartbeat
+	Rejected bool
+}
+
+// BatchResult maps each submitted heartbeat name to its outcome
+type BatchResult struct {
+	Items map[string]BatchItemResult
+}
+
+// Failed returns the names of rejected heartbeats in sorted order, so only
+// those need to be resent
+func (r *BatchResult) Failed() []string {
+	var names []string
+	for name, item := range r.Items {
+		if item.Rejected {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// batchResponse is the envelope returned by the batch endpoint
+type batchResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Results struct {
+		Rejected []struct {
+			HeartbeatName string `json:"heartbeat_name"`
+			StatusCode    int    `json:"status_code"`
+			Message       string `json:"message"`
+		} `json:"rejected"`
+	} `json:"results"`
+}
+
+// SendHeartbeats sends multiple heartbeats in one request using the default client
+func SendHeartbeats(hs []Heartbeat) error {
+	return defaultClient().SendHeartbeats(hs)
+}
+
+// SendHeartbeats sends multiple heartbeats to medic in one request
+func (c *Client) SendHeartbeats(hs []Heartbeat) error {
+	return c.SendHeartbeatsContext(context.Background(), hs)
+}
+
+// SendHeartbeatsContext sends multiple heartbeats to medic in one request.
+// If any heartbeat fails validation nothing is sent and a *BatchError listing
+// the invalid indices is returned; heartbeats the server rejects are reported
+// the same way by name, with the per-heartbeat outcome in its Result
+func (c *Client) SendHeartbeatsContext(ctx context.Context, hs []Heartbeat, opts ...RequestOption) error {
+	_, err := c.SendHeartbeatsWithResult(ctx, hs, opts...)
+	return err
+}
+
+// SendHeartbeatsWithResult is like SendHeartbeatsContext, but also returns the
+// outcome of each heartbeat once the batch was sent, including when the server
+// refused part of it. The result is nil if nothing was sent, or if the server
+// failed the batch without listing per-heartbeat outcomes
+func (c *Client) SendHeartbeatsWithResult(ctx context.Context, hs []Heartbeat, opts ...RequestOption) (result *BatchResult, err error) {
+	if c.disabled() {
+		return nil, nil
+	}
+	ctx, end := c.startSpan(ctx, "medic.SendHeartbeats")
+	var resp *response
+	rejected := false
+	defer func() {
+		end(resp, err)
+		c.recordOutcome(rejected, statusCode(resp, err), err)
+	}()
+
+	// Validate every heartbeat before making any request
+	invalid := map[int]error{}
+	for i, h := range hs {
+		if err := c.validate(h); err != nil {
+			invalid[i] = err
+		}
+	}
+	if len(invalid) > 0 {
+		rejected = true
+		return nil, &BatchError{Invalid: invalid}
+	}
+
+	// Configure the body content
+	body, err := c.marshalBatch(hs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode heartbeats: %w", err)
+	}
+
+	// Make the request to medic
+	rc := newRequestConfig(opts)
+	if invalid := c.validateBatchBody(ctx, c.baseURL(rc), body); invalid != nil {
+		rejected = true
+		return nil, &BatchError{Invalid: invalid}
+	}
+	url := fmt.Sprintf("%s/heartbeat/batch", c.baseURL(rc))
+	label := fmt.Sprintf("batch of %d", len(hs))
+	resp, err = c.doWithRetry(ctx, http.MethodPost, url, body, label, rc)
+	if err != nil {
+		// A refused batch may still list which heartbeats were at fault
+		var se *StatusError
+		if errors.As(err, &se) {
+			if result, be := batchOutcome(hs, se.StatusCode, se.Body, true); be != nil {
+				be.Err = err
+				return result, be
+			}
+		}
+		return nil, err
+	}
+
+	// Surface any heartbeats the server refused
+	result, be := batchOutcome(hs, resp.StatusCode, resp.Body, false)
+	if be != nil {
+		return result, be
+	}
+	return result, nil
+}
+
+// batchOutcome builds the per-heartbeat result from a batch response body,
+// with a *BatchError when the server listed rejected heartbeats. For a
+// failed request the result is nil unless the body lists rejections
+func batchOutcome(hs []Heartbeat, code int, body []byte, failed bool) (*BatchResult, *BatchError) {
+	var br batchResponse
+	parsed := len(bytes.TrimSpace(body)) > 0 && json.Unmarshal(body, &br) == nil
+	if failed && (!parsed || len(br.Results.Rejected) == 0) {
+		return nil, nil
+	}
+
+	result := &BatchResult{Items: make(map[string]BatchItemResult, len(hs))}
+	for _, h := range hs {
+		result.Items[h.HeartbeatName] = BatchItemResult{StatusCode: code}
+	}
+	if !parsed || len(br.Results.Rejected) == 0 {
+		return result, nil
+	}
+	rejected := make([]string, len(br.Results.Rejected))
+	for i, r := range br.Results.Rejected {
+		rejected[i] = r.HeartbeatName
+		item := BatchItemResult{StatusCode: r.StatusCode, Message: r.Message, Rejected: true}
+		if item.StatusCode == 0 {
+			item.StatusCode = code
+		}
+		result.Items[r.HeartbeatName] = item
+	}
+	return result, &BatchError{Rejected: rejected, Result: result}
+}
+
+// SendHeartbeatsConcurrent sends each heartbeat as its own request on a pool
+// of at most concurrency goroutines. It waits for every send and returns all
+// failures joined with errors.Join, each naming its index and heartbeat. Once
+// ctx is done no new sends start, and the context error is included for the
+// heartbeats that were skipped
+func (c *Client) SendHeartbeatsConcurrent(ctx context.Context, hs []Heartbeat, concurrency int, opts ...RequestOption) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(hs) {
+		concurrency = len(hs)
+	}
+
+	errs := make([]error, len(hs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := c.SendHeartbeatContext(ctx, hs[i], opts...); err != nil {
+					errs[i] = fmt.Errorf("heartbeat %d (%s): %w", i, hs[i].HeartbeatName, err)
+				}
+			}
+		}()
+	}
+
+	skipped := false
+feed:
+	for i := range hs {
+		select {
+		case <-ctx.Done():
+			skipped = true
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if skipped {
+		errs = append(errs, fmt.Errorf("stopped launching heartbeats: %w", ctx.Err()))
+	}
+	return errors.Join(errs...)
+}
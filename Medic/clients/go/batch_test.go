@@ -0,0 +1,182 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendHeartbeats(t *testing.T) {
+	var received []Heartbeat
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/heartbeat/batch" {
+			t.Errorf("path = %q, want /heartbeat/batch", r.URL.Path)
+		}
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode batch: %v", err)
+		}
+		for _, h := range received {
+			if h.HeartbeatName == "rejected-hb" {
+				w.WriteHeader(http.StatusMultiStatus)
+				w.Write([]byte(`{"success":false,"message":"","results":{"rejected":[{"heartbeat_name":"rejected-hb","message":"not registered"}]}}`))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true,"message":"","results":""}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	t.Run("testing success", func(t *testing.T) {
+		hs := []Heartbeat{{HeartbeatName: "a-hb", Status: "UP"}, {HeartbeatName: "b-hb", Status: "UP"}}
+		if err := c.SendHeartbeats(hs); err != nil {
+			t.Fatalf("SendHeartbeats() unexpected error = %v", err)
+		}
+		if len(received) != 2 {
+			t.Errorf("server received %d heartbeats, want 2", len(received))
+		}
+	})
+
+	t.Run("testing invalid indices", func(t *testing.T) {
+		received = nil
+		hs := []Heartbeat{{HeartbeatName: "a-hb", Status: "UP"}, {Status: "UP"}, {Status: "DOWN"}}
+		err := c.SendHeartbeats(hs)
+		var be *BatchError
+		if !errors.As(err, &be) {
+			t.Fatalf("SendHeartbeats() error = %v, want *BatchError", err)
+		}
+		if len(be.Invalid) != 2 || be.Invalid[1] == nil || be.Invalid[2] == nil {
+			t.Errorf("Invalid = %v, want indices 1 and 2", be.Invalid)
+		}
+		if received != nil {
+			t.Errorf("batch was sent despite validation failure")
+		}
+	})
+
+	t.Run("testing partial rejection", func(t *testing.T) {
+		hs := []Heartbeat{{HeartbeatName: "a-hb", Status: "UP"}, {HeartbeatName: "rejected-hb", Status: "UP"}}
+		err := c.SendHeartbeats(hs)
+		var be *BatchError
+		if !errors.As(err, &be) {
+			t.Fatalf("SendHeartbeats() error = %v, want *BatchError", err)
+		}
+		if len(be.Rejected) != 1 || be.Rejected[0] != "rejected-hb" {
+			t.Errorf("Rejected = %v, want [rejected-hb]", be.Rejected)
+		}
+	})
+}
+
+func TestSendHeartbeatsWithResult(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var hs []Heartbeat
+		json.NewDecoder(r.Body).Decode(&hs)
+		if len(hs) > 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"message":"invalid heartbeats","results":{"rejected":[{"heartbeat_name":"c-hb","status_code":404,"message":"not registered"}]}}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true,"message":"","results":""}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	ctx := context.Background()
+
+	t.Run("testing success", func(t *testing.T) {
+		hs := []Heartbeat{{HeartbeatName: "a-hb", Status: "UP"}, {HeartbeatName: "b-hb", Status: "UP"}}
+		result, err := c.SendHeartbeatsWithResult(ctx, hs)
+		if err != nil {
+			t.Fatalf("SendHeartbeatsWithResult() unexpected error = %v", err)
+		}
+		want := map[string]BatchItemResult{"a-hb": {StatusCode: http.StatusCreated}, "b-hb": {StatusCode: http.StatusCreated}}
+		if !reflect.DeepEqual(result.Items, want) {
+			t.Errorf("Items = %+v, want %+v", result.Items, want)
+		}
+	})
+
+	t.Run("testing per-item failure on 400", func(t *testing.T) {
+		hs := []Heartbeat{{HeartbeatName: "a-hb", Status: "UP"}, {HeartbeatName: "b-hb", Status: "UP"}, {HeartbeatName: "c-hb", Status: "UP"}}
+		result, err := c.SendHeartbeatsWithResult(ctx, hs)
+		var be *BatchError
+		if !errors.As(err, &be) || be.Result != result {
+			t.Fatalf("SendHeartbeatsWithResult() error = %v, want *BatchError holding the result", err)
+		}
+		var se *StatusError
+		if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+			t.Errorf("error = %v, want to wrap *StatusError with code 400", err)
+		}
+		if got := result.Failed(); !reflect.DeepEqual(got, []string{"c-hb"}) {
+			t.Errorf("Failed() = %v, want [c-hb]", got)
+		}
+		want := BatchItemResult{StatusCode: http.StatusNotFound, Message: "not registered", Rejected: true}
+		if got := result.Items["c-hb"]; got != want {
+			t.Errorf("Items[c-hb] = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("testing invalid batch has no result", func(t *testing.T) {
+		result, err := c.SendHeartbeatsWithResult(ctx, []Heartbeat{{Status: "UP"}})
+		if err == nil || result != nil {
+			t.Errorf("SendHeartbeatsWithResult() = %v, %v, want nil result and an error", result, err)
+		}
+	})
+}
+
+func TestSendHeartbeatsConcurrent(t *testing.T) {
+	var inFlight, peak int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		var h Heartbeat
+		json.NewDecoder(r.Body).Decode(&h)
+		if strings.HasSuffix(h.HeartbeatName, "-bad") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	hs := make([]Heartbeat, 10)
+	for i := range hs {
+		hs[i] = Heartbeat{HeartbeatName: fmt.Sprintf("staging-fake-heartbeat-hb-%d", i), Status: StatusUp}
+	}
+	hs[3].HeartbeatName += "-bad"
+	hs[7].HeartbeatName += "-bad"
+
+	err := NewClient(srv.URL).SendHeartbeatsConcurrent(context.Background(), hs, 3)
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Fatalf("SendHeartbeatsConcurrent() error = %v, want joined 404s", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "heartbeat 3 ") || !strings.Contains(msg, "heartbeat 7 ") {
+		t.Errorf("error %q should name heartbeats 3 and 7", msg)
+	}
+	if p := atomic.LoadInt32(&peak); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+
+	// A cancelled context launches nothing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewClient(srv.URL).SendHeartbeatsConcurrent(ctx, hs, 3); !errors.Is(err, context.Canceled) {
+		t.Errorf("SendHeartbeatsConcurrent() error = %v, want context.Canceled", err)
+	}
+}
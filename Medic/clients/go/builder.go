@@ -0,0 +1,66 @@
+package medic
+
+import "time"
+
+// HeartbeatBuilder builds a Heartbeat with chainable setters and validates it in Build
+type HeartbeatBuilder struct {
+	h Heartbeat
+}
+
+// NewHeartbeat starts building a heartbeat with the given name
+func NewHeartbeat(name string) *HeartbeatBuilder {
+	return &HeartbeatBuilder{h: Heartbeat{HeartbeatName: name}}
+}
+
+// Service sets the service name
+func (b *HeartbeatBuilder) Service(service string) *HeartbeatBuilder {
+	b.h.Service = service
+	return b
+}
+
+// Services adds services the heartbeat also reports for
+func (b *HeartbeatBuilder) Services(services ...string) *HeartbeatBuilder {
+	b.h.Services = append(b.h.Services, services...)
+	return b
+}
+
+// Status sets the status
+func (b *HeartbeatBuilder) Status(status Status) *HeartbeatBuilder {
+	b.h.Status = status
+	return b
+}
+
+// Severity sets how urgently Medic should alert on the heartbeat
+func (b *HeartbeatBuilder) Severity(severity Severity) *HeartbeatBuilder {
+	b.h.Severity = severity
+	return b
+}
+
+// Metadata adds a key/value pair to the heartbeat's metadata
+func (b *HeartbeatBuilder) Metadata(key, value string) *HeartbeatBuilder {
+	if b.h.Metadata == nil {
+		b.h.Metadata = map[string]string{}
+	}
+	b.h.Metadata[key] = value
+	return b
+}
+
+// Interval sets how often the heartbeat is sent
+func (b *HeartbeatBuilder) Interval(d time.Duration) *HeartbeatBuilder {
+	b.h.Interval = d
+	return b
+}
+
+// Timestamp sets when the heartbeat event occurred
+func (b *HeartbeatBuilder) Timestamp(t time.Time) *HeartbeatBuilder {
+	b.h.Timestamp = t
+	return b
+}
+
+// Build validates and returns the heartbeat
+func (b *HeartbeatBuilder) Build() (Heartbeat, error) {
+	if err := b.h.Validate(); err != nil {
+		return Heartbeat{}, err
+	}
+	return b.h, nil
+}
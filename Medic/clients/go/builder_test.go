@@ -0,0 +1,31 @@
+package medic
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHeartbeatBuilder(t *testing.T) {
+	h, err := NewHeartbeat("staging-fake-heartbeat-hb").Service("fakeservice").Services("billing", "invoices").Status(StatusUp).Severity(SeverityInfo).Metadata("region", "us-east-1").Build()
+	if err != nil {
+		t.Fatalf("Build() unexpected error = %v", err)
+	}
+	want := Heartbeat{
+		HeartbeatName: "staging-fake-heartbeat-hb",
+		Service:       "fakeservice",
+		Services:      []string{"billing", "invoices"},
+		Status:        StatusUp,
+		Severity:      SeverityInfo,
+		Metadata:      map[string]string{"region": "us-east-1"},
+	}
+	if !reflect.DeepEqual(h, want) {
+		t.Errorf("Build() = %+v, want %+v", h, want)
+	}
+
+	_, err = NewHeartbeat("staging-fake-heartbeat-hb").Status("Up").Build()
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Field != "status" {
+		t.Errorf("Build() error = %v, want status ValidationError", err)
+	}
+}
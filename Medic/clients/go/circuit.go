@@ -0,0 +1,107 @@
+package medic
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without making a request while the circuit
+// breaker is open after repeated failures
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// WithCircuitBreaker stops sending to Medic after failures consecutive failed
+// requests. While the circuit is open, requests fail fast with ErrCircuitOpen.
+// After cooldown, a single probe request is let through: if it succeeds the
+// circuit closes, and if it fails the circuit stays open for another cooldown.
+// Only connection errors and retryable statuses (429 and 5xx) count as failures.
+// Each of the FallbackURLs has its own breaker with the same settings, so a
+// failing primary never blocks a healthy standby. A non-positive failures
+// disables the breaker
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if failures <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &circuitBreaker{threshold: failures, cooldown: cooldown}
+	}
+}
+
+// circuitBreaker tracks consecutive failures shared by every request of a
+// client to its primary endpoint
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+	// fallbacks holds the breaker of each fallback endpoint, by base URL
+	fallbacks map[string]*circuitBreaker
+}
+
+// forFallback returns the breaker for the fallback endpoint base, creating it
+// with the same settings on first use
+func (b *circuitBreaker) forFallback(base string) *circuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	fb, ok := b.fallbacks[base]
+	if !ok {
+		if b.fallbacks == nil {
+			b.fallbacks = map[string]*circuitBreaker{}
+		}
+		fb = &circuitBreaker{threshold: b.threshold, cooldown: b.cooldown}
+		b.fallbacks[base] = fb
+	}
+	return fb
+}
+
+// allow reports whether a request may be made now
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || now.Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request. A neutral outcome,
+// such as a cancelled request, neither resets nor adds to the failure count
+func (b *circuitBreaker) record(now time.Time, failed, neutral bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch {
+	case neutral:
+	case failed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = now
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+// circuit returns the breaker for the endpoint rc targets, or nil when the
+// client has none
+func (c *Client) circuit(rc *requestConfig) *circuitBreaker {
+	if c.breaker == nil || rc.fallback == "" {
+		return c.breaker
+	}
+	return c.breaker.forFallback(rc.fallback)
+}
+
+// recordCircuit reports the outcome of a request attempt to b
+func (c *Client) recordCircuit(b *circuitBreaker, result attemptResult, err error) {
+	var se *StatusError
+	neutral := err != nil && !result.transient && !errors.As(err, &se)
+	b.record(c.clock().Now(), result.transient, neutral)
+}
@@ -0,0 +1,189 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var calls int32
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if healthy.Load() {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	clock := newFakeClock()
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithRetry(RetryConfig{MaxAttempts: 1}),
+		WithCircuitBreaker(2, time.Minute),
+		WithClock(clock),
+	)
+
+	// Two failures open the circuit
+	for i := 0; i < 2; i++ {
+		if err := c.SendHeartbeat(h); errors.Is(err, ErrCircuitOpen) || err == nil {
+			t.Fatalf("send %d error = %v, want a status error", i, err)
+		}
+	}
+	if err := c.SendHeartbeat(h); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("SendHeartbeat() error = %v, want ErrCircuitOpen", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("server called %d times, want 2 while open", n)
+	}
+
+	// After the cooldown a failed probe reopens the circuit
+	clock.Advance(time.Minute)
+	if err := c.SendHeartbeat(h); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe error = %v, want the request to be sent", err)
+	}
+	if err := c.SendHeartbeat(h); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("SendHeartbeat() after failed probe error = %v, want ErrCircuitOpen", err)
+	}
+
+	// A successful probe closes it again
+	healthy.Store(true)
+	clock.Advance(time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("send %d after recovery error = %v", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithCircuitBreaker(1, time.Minute))
+	for i := 0; i < 3; i++ {
+		if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb"}); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("send %d error = %v, a 404 should not open the circuit", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerProbeNotSent(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	errEditor := errors.New("editor failed")
+	var failEditor atomic.Bool
+	clock := newFakeClock()
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithRetry(RetryConfig{MaxAttempts: 1}),
+		WithCircuitBreaker(1, time.Minute),
+		WithRateLimit(10, 1),
+		WithClock(clock),
+		WithRequestEditor(func(req *http.Request) error {
+			if failEditor.Load() {
+				return errEditor
+			}
+			return nil
+		}),
+	)
+	if err := c.SendHeartbeat(h); err == nil {
+		t.Fatal("SendHeartbeat() expected a status error")
+	}
+	healthy.Store(true)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		send func() error
+	}{
+		{name: "testing rate limit wait cancelled", send: func() error {
+			_, err := c.Do(cancelled, h)
+			return err
+		}},
+		{name: "testing request editor error", send: func() error {
+			failEditor.Store(true)
+			defer failEditor.Store(false)
+			_, err := c.Do(context.Background(), h)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each probe admitted after the cooldown fails before it is sent
+			clock.Advance(time.Minute)
+			if err := tt.send(); err == nil || errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("Do() error = %v, want the probe admitted and failed", err)
+			}
+			if err := c.SendHeartbeat(h); err != nil {
+				t.Errorf("SendHeartbeat() after the failed probe error = %v, want the next probe sent", err)
+			}
+			// Trip the breaker again for the next case
+			healthy.Store(false)
+			c.SendHeartbeat(h)
+			healthy.Store(true)
+		})
+	}
+}
+
+func TestCircuitBreakerWithFallbackURLs(t *testing.T) {
+	var primaryHits, standbyHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	standby := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		standbyHits.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer standby.Close()
+
+	c := NewClientWithOptions(
+		WithBaseURL(primary.URL),
+		WithFallbackURLs(standby.URL),
+		WithCircuitBreaker(3, time.Minute),
+		WithClock(newFakeClock()),
+	)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	tests := []struct {
+		name        string
+		wantPrimary int32
+	}{
+		{name: "testing primary trips its breaker", wantPrimary: 3},
+		{name: "testing open primary circuit goes to standby", wantPrimary: 3},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.SendHeartbeat(h); err != nil {
+				t.Fatalf("SendHeartbeat() error = %v, want the standby to succeed", err)
+			}
+			if got := primaryHits.Load(); got != tt.wantPrimary {
+				t.Errorf("primary hits = %d, want %d", got, tt.wantPrimary)
+			}
+			if got, want := standbyHits.Load(), int32(i+1); got != want {
+				t.Errorf("standby hits = %d, want %d", got, want)
+			}
+		})
+	}
+}
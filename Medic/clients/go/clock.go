@@ -0,0 +1,33 @@
+package medic
+
+import "time"
+
+// Clock tells time for retry backoff, Retry-After handling, and the circuit
+// breaker. Swap in a fake with WithClock to test time-dependent behavior
+// without sleeping
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the time once d has elapsed
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets the clock the client uses for backoff and the circuit breaker
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.Clock = clock
+	}
+}
+
+// clock returns the configured Clock, defaulting to the real clock
+func (c *Client) clock() Clock {
+	if c.Clock == nil {
+		return realClock{}
+	}
+	return c.Clock
+}
@@ -0,0 +1,109 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClientClosed is returned by requests, async sends, and Monitor.Start on
+// a client after Close
+var ErrClientClosed = errors.New("medic client is closed")
+
+// lifecycle tracks a client's background work so Close can stop it
+type lifecycle struct {
+	closed atomic.Bool
+
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	monitors map[*Monitor]struct{}
+	async    sync.WaitGroup
+}
+
+// Close stops every running Monitor that uses the client, cancels and waits
+// for in-flight async sends, and closes idle connections. A closed client
+// must not be reused: later requests fail with ErrClientClosed. Closing an
+// already closed client does nothing
+func (c *Client) Close() error {
+	l := &c.lifecycle
+	l.mu.Lock()
+	if l.closed.Swap(true) {
+		l.mu.Unlock()
+		return nil
+	}
+	if l.cancel != nil {
+		l.cancel()
+	}
+	monitors := make([]*Monitor, 0, len(l.monitors))
+	for m := range l.monitors {
+		monitors = append(monitors, m)
+	}
+	l.mu.Unlock()
+
+	for _, m := range monitors {
+		m.Stop()
+	}
+	l.async.Wait()
+	if d, ok := c.doer().(interface{ CloseIdleConnections() }); ok {
+		d.CloseIdleConnections()
+	}
+	return nil
+}
+
+// checkClosed returns ErrClientClosed once the client is closed
+func (c *Client) checkClosed() error {
+	if c.lifecycle.closed.Load() {
+		return ErrClientClosed
+	}
+	return nil
+}
+
+// trackAsync registers an async send, returning a context that Close cancels
+// and a func to call when the send is done
+func (c *Client) trackAsync(ctx context.Context) (context.Context, func(), error) {
+	l := &c.lifecycle
+	l.mu.Lock()
+	if l.closed.Load() {
+		l.mu.Unlock()
+		return nil, nil, ErrClientClosed
+	}
+	if l.ctx == nil {
+		l.ctx, l.cancel = context.WithCancel(context.Background())
+	}
+	closing := l.ctx
+	l.async.Add(1)
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(closing, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		l.async.Done()
+	}, nil
+}
+
+// addMonitor registers a started monitor so Close can stop it
+func (c *Client) addMonitor(m *Monitor) error {
+	l := &c.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed.Load() {
+		return ErrClientClosed
+	}
+	if l.monitors == nil {
+		l.monitors = map[*Monitor]struct{}{}
+	}
+	l.monitors[m] = struct{}{}
+	return nil
+}
+
+// removeMonitor forgets a monitor once it stops
+func (c *Client) removeMonitor(m *Monitor) {
+	l := &c.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.monitors, m)
+}
@@ -0,0 +1,79 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClose(t *testing.T) {
+	release := make(chan struct{})
+	blocked := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Block") != "" {
+			blocked <- struct{}{}
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	defer close(release)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}
+
+	t.Run("testing close stops monitors and async sends", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithRetry(RetryConfig{MaxAttempts: 1}))
+		m := NewMonitor(c)
+		if err := m.Start(context.Background(), h, time.Hour); err != nil {
+			t.Fatalf("Start() unexpected error = %v", err)
+		}
+		result := c.SendHeartbeatAsyncContext(context.Background(), h, WithRequestHeader("X-Block", "1"))
+		<-blocked
+
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close() unexpected error = %v", err)
+		}
+		m.mu.Lock()
+		running := m.done != nil
+		m.mu.Unlock()
+		if running {
+			t.Errorf("monitor still running after Close()")
+		}
+		select {
+		case err := <-result:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("async send error = %v, want context.Canceled", err)
+			}
+		default:
+			t.Errorf("Close() returned before the async send finished")
+		}
+		if err := c.Close(); err != nil {
+			t.Errorf("second Close() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("testing closed client rejects work", func(t *testing.T) {
+		c := NewClient(srv.URL)
+		c.Close()
+		if err := c.SendHeartbeat(h); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("SendHeartbeat() error = %v, want ErrClientClosed", err)
+		}
+		if _, err := c.Do(context.Background(), h); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Do() error = %v, want ErrClientClosed", err)
+		}
+		if err := c.Ping(context.Background()); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Ping() error = %v, want ErrClientClosed", err)
+		}
+		if err := <-c.SendHeartbeatAsync(h); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("SendHeartbeatAsync() error = %v, want ErrClientClosed", err)
+		}
+		if err := NewMonitor(c).Start(context.Background(), h, time.Hour); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("Monitor.Start() error = %v, want ErrClientClosed", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+// Command medic sends a heartbeat to Medic from the command line, for shell
+// scripts, cron jobs and CI steps that aren't written in Go:
+//
+//	medic --name nightly-backup-hb --service backups --status UP
+//
+// With --watch it runs as a sidecar, sending the heartbeat every --interval
+// until SIGINT or SIGTERM, then sending a final DOWN. The base URL and token
+// default to MEDIC_BASE_URL and MEDIC_API_TOKEN, and the heartbeat fields to
+// MEDIC_HEARTBEAT_NAME, MEDIC_SERVICE and MEDIC_STATUS.
+//
+// It exits 0 on success, 1 when the heartbeat could not be sent, and 2 for
+// invalid flags or heartbeat fields.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailed  = 1
+	exitInvalid = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run sends the heartbeat described by args and returns the exit code
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("medic", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("name", os.Getenv("MEDIC_HEARTBEAT_NAME"), "heartbeat name (env MEDIC_HEARTBEAT_NAME)")
+	service := fs.String("service", os.Getenv("MEDIC_SERVICE"), "service name (env MEDIC_SERVICE)")
+	status := fs.String("status", envOr("MEDIC_STATUS", string(medic.StatusUp)), "UP, DOWN or DEGRADED (env MEDIC_STATUS)")
+	baseURL := fs.String("base-url", "", "Medic base URL (default env MEDIC_BASE_URL)")
+	token := fs.String("token", "", "API bearer token (default env MEDIC_API_TOKEN)")
+	timeout := fs.Duration("timeout", medic.GetTimeout(), "timeout for each request (env MEDIC_TIMEOUT)")
+	watch := fs.Bool("watch", false, "keep sending every --interval until SIGINT or SIGTERM, then send DOWN")
+	interval := fs.Duration("interval", 30*time.Second, "interval between heartbeats with --watch")
+	if err := fs.Parse(args); err != nil {
+		return exitInvalid
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "medic: unexpected arguments: %v\n", fs.Args())
+		return exitInvalid
+	}
+
+	st, err := medic.ParseStatus(*status)
+	if err != nil {
+		fmt.Fprintf(stderr, "medic: %v\n", err)
+		return exitInvalid
+	}
+	h := medic.Heartbeat{HeartbeatName: *name, Service: *service, Status: st}
+	if err := medic.ValidateHeartbeat(h); err != nil {
+		fmt.Fprintf(stderr, "medic: invalid heartbeat: %v\n", err)
+		return exitInvalid
+	}
+
+	opts := []medic.Option{medic.WithTimeout(*timeout)}
+	if *token != "" {
+		opts = append(opts, medic.WithBearerToken(*token))
+	}
+	client, err := medic.NewClientStrict(*baseURL, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "medic: invalid configuration: %v\n", err)
+		return exitInvalid
+	}
+	defer client.Close()
+
+	if *watch {
+		err = client.MonitorUntilSignal(h, *interval)
+	} else {
+		err = client.SendHeartbeat(h)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "medic: failed to send heartbeat %s: %v\n", h.HeartbeatName, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// envOr returns the environment variable key, or fallback when it is unset or empty
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
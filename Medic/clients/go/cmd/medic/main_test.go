@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+func TestRun(t *testing.T) {
+	var got medic.Heartbeat
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		auth = r.Header.Get("Authorization")
+		if got.Service == "slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+		if got.Service == "rejected" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	t.Setenv("MEDIC_BASE_URL", "")
+	t.Setenv("MEDIC_API_TOKEN", "")
+	t.Setenv("MEDIC_HEARTBEAT_NAME", "")
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "testing flags",
+			args:     []string{"--name", "staging-fake-heartbeat-hb", "--service", "fakeservice", "--status", "degraded", "--base-url", srv.URL, "--token", "secret"},
+			wantCode: exitOK,
+		},
+		{
+			name:     "testing env vars",
+			args:     []string{"--service", "fakeservice"},
+			env:      map[string]string{"MEDIC_HEARTBEAT_NAME": "staging-fake-heartbeat-hb", "MEDIC_BASE_URL": srv.URL, "MEDIC_STATUS": "DEGRADED", "MEDIC_API_TOKEN": "secret"},
+			wantCode: exitOK,
+		},
+		{
+			name:     "testing server rejection",
+			args:     []string{"--name", "staging-fake-heartbeat-hb", "--service", "rejected", "--base-url", srv.URL, "--timeout", "1s"},
+			wantCode: exitFailed,
+			wantErr:  "failed to send heartbeat staging-fake-heartbeat-hb",
+		},
+		{
+			name:     "testing env timeout",
+			args:     []string{"--name", "staging-fake-heartbeat-hb", "--service", "slow", "--base-url", srv.URL},
+			env:      map[string]string{"MEDIC_TIMEOUT": "20ms"},
+			wantCode: exitFailed,
+			wantErr:  "Client.Timeout exceeded",
+		},
+		{
+			name:     "testing missing name",
+			args:     []string{"--base-url", srv.URL},
+			wantCode: exitInvalid,
+			wantErr:  "heartbeat_name is required",
+		},
+		{
+			name:     "testing invalid status",
+			args:     []string{"--name", "staging-fake-heartbeat-hb", "--status", "sideways"},
+			wantCode: exitInvalid,
+			wantErr:  "invalid status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			var stderr bytes.Buffer
+			if code := run(tt.args, &stderr); code != tt.wantCode {
+				t.Fatalf("run() = %d, want %d; stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantErr)
+			}
+			if tt.wantCode == exitOK && (got.Status != medic.StatusDegraded || auth != "Bearer secret") {
+				t.Errorf("sent status %q with Authorization %q, want DEGRADED with the token", got.Status, auth)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package medic
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// DefaultCompressionThreshold is the body size WithCompression uses when
+// given a non-positive threshold
+const DefaultCompressionThreshold = 1024
+
+// WithCompression gzips request bodies of at least threshold bytes and sends
+// them with "Content-Encoding: gzip". Smaller bodies are sent as is, since
+// compressing them costs more than it saves. Only enable it against a Medic
+// server that accepts gzip-encoded requests
+func WithCompression(threshold int) Option {
+	return func(c *Client) {
+		if threshold <= 0 {
+			threshold = DefaultCompressionThreshold
+		}
+		c.CompressionThreshold = threshold
+	}
+}
+
+// compress gzips payload when compression is enabled and the payload is large
+// enough, returning the body to send and its Content-Encoding
+func (c *Client) compress(payload []byte) ([]byte, string) {
+	if c.CompressionThreshold <= 0 || len(payload) < c.CompressionThreshold {
+		return payload, ""
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(payload); err != nil {
+		return payload, ""
+	}
+	if err := zw.Close(); err != nil {
+		return payload, ""
+	}
+	return buf.Bytes(), "gzip"
+}
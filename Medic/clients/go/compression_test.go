@@ -0,0 +1,62 @@
+package medic
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithCompression(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		count    int
+		wantGzip bool
+	}{
+		{name: "testing disabled by default", count: 100},
+		{name: "testing below threshold", opts: []Option{WithCompression(0)}, count: 1},
+		{name: "testing above threshold", opts: []Option{WithCompression(0)}, count: 100, wantGzip: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var encoding string
+			var got []Heartbeat
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+				var body io.Reader = r.Body
+				if encoding == "gzip" {
+					zr, err := gzip.NewReader(r.Body)
+					if err != nil {
+						t.Errorf("invalid gzip body: %v", err)
+						return
+					}
+					body = zr
+				}
+				if err := json.NewDecoder(body).Decode(&got); err != nil {
+					t.Errorf("failed to decode body: %v", err)
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer srv.Close()
+
+			hs := make([]Heartbeat, tt.count)
+			for i := range hs {
+				hs[i] = Heartbeat{HeartbeatName: fmt.Sprintf("staging-fake-heartbeat-hb-%d", i), Status: StatusUp}
+			}
+			c := NewClientWithOptions(append([]Option{WithBaseURL(srv.URL)}, tt.opts...)...)
+			if err := c.SendHeartbeats(hs); err != nil {
+				t.Fatalf("SendHeartbeats() unexpected error = %v", err)
+			}
+			if (encoding == "gzip") != tt.wantGzip {
+				t.Errorf("Content-Encoding = %q, wantGzip %v", encoding, tt.wantGzip)
+			}
+			if len(got) != tt.count {
+				t.Errorf("server decoded %d heartbeats, want %d", len(got), tt.count)
+			}
+		})
+	}
+}
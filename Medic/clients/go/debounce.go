@@ -0,0 +1,91 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrStatusFlapping is returned by Monitor.Send when WithRejectFlapping is set
+// and a heartbeat's status changes again within the debounce window
+var ErrStatusFlapping = errors.New("heartbeat status changed within the debounce window")
+
+// MonitorOption configures a Monitor created with NewMonitor
+type MonitorOption func(*Monitor)
+
+// WithStatusDebounce makes the monitor track the last status sent for each
+// heartbeat name and flag a different status sent less than d later, which
+// usually means two goroutines disagree about the service's state. Flapping
+// sends are logged and still sent, unless WithRejectFlapping is also given
+func WithStatusDebounce(d time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.debounce.window = d
+	}
+}
+
+// WithRejectFlapping makes a flapping send fail with ErrStatusFlapping instead
+// of being logged and sent. It has no effect without WithStatusDebounce
+func WithRejectFlapping() MonitorOption {
+	return func(m *Monitor) {
+		m.debounce.reject = true
+	}
+}
+
+// statusDebounce remembers the last status sent for each heartbeat name
+type statusDebounce struct {
+	window time.Duration
+	reject bool
+
+	mu   sync.Mutex
+	last map[string]sentStatus
+}
+
+// sentStatus is a status and when it was sent
+type sentStatus struct {
+	status Status
+	at     time.Time
+}
+
+// check records h's status, reporting an error if it contradicts a status sent
+// within the window. A rejected status is not recorded
+func (d *statusDebounce) check(h Heartbeat, now time.Time) error {
+	if d.window <= 0 {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var err error
+	if prev, ok := d.last[h.HeartbeatName]; ok && prev.status != h.Status && now.Sub(prev.at) < d.window {
+		err = fmt.Errorf("%w: %s went from %s to %s after %v", ErrStatusFlapping,
+			h.HeartbeatName, prev.status, h.Status, now.Sub(prev.at))
+		if d.reject {
+			return err
+		}
+	}
+	d.record(h, now)
+	return err
+}
+
+// record stores h's status without checking it; the caller holds d.mu
+func (d *statusDebounce) record(h Heartbeat, now time.Time) {
+	if d.last == nil {
+		d.last = map[string]sentStatus{}
+	}
+	d.last[h.HeartbeatName] = sentStatus{status: h.Status, at: now}
+}
+
+// Send delivers h once through the monitor's client, outside the ticker loop.
+// With WithStatusDebounce, a status that contradicts one sent for the same
+// heartbeat within the window is logged, or rejected with ErrStatusFlapping
+// when WithRejectFlapping is set
+func (m *Monitor) Send(ctx context.Context, h Heartbeat) error {
+	if err := m.debounce.check(h, m.client.clock().Now()); err != nil {
+		if m.debounce.reject {
+			return err
+		}
+		m.client.logFlapping(h.HeartbeatName, err)
+	}
+	return m.client.SendHeartbeatContext(ctx, h)
+}
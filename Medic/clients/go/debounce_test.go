@@ -0,0 +1,62 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitorStatusDebounce(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	clock := newFakeClock()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock))
+	up := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	down := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusDown}
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		opts      []MonitorOption
+		wantErr   error
+		wantCalls int32
+	}{
+		{name: "testing debounce off", wantCalls: 2},
+		{name: "testing flapping logged and sent", opts: []MonitorOption{WithStatusDebounce(time.Minute)}, wantCalls: 2},
+		{name: "testing flapping rejected", opts: []MonitorOption{WithStatusDebounce(time.Minute), WithRejectFlapping()}, wantErr: ErrStatusFlapping, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			m := NewMonitor(c, tt.opts...)
+			if err := m.Send(ctx, up); err != nil {
+				t.Fatalf("Send(UP) unexpected error = %v", err)
+			}
+			if err := m.Send(ctx, down); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Send(DOWN) error = %v, want %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("server called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+
+	// Outside the window a change is a normal transition
+	m := NewMonitor(c, WithStatusDebounce(time.Minute), WithRejectFlapping())
+	if err := m.Send(ctx, up); err != nil {
+		t.Fatalf("Send(UP) unexpected error = %v", err)
+	}
+	clock.Advance(2 * time.Minute)
+	if err := m.Send(ctx, down); err != nil {
+		t.Errorf("Send(DOWN) after the window error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,46 @@
+package medic
+
+import "sync"
+
+// DefaultClient is used by the package-level functions such as SendHeartbeat.
+// When nil, each call builds a fresh client from the environment like
+// NewClient(""), and these clients share one transport so connections are
+// reused across calls. Configure it once at startup, before any heartbeat is sent,
+// or use SetDefaultClient, which is safe to call at any time
+var DefaultClient *Client
+
+// defaultMu guards DefaultClient for SetDefaultClient
+var defaultMu sync.RWMutex
+
+// SetDefaultClient replaces DefaultClient, so the package-level functions use
+// c with its authentication, timeout, logging and other options. Passing nil
+// restores the per-call clients built from the environment
+func SetDefaultClient(c *Client) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	DefaultClient = c
+}
+
+// defaultTransport is shared by the clients defaultClient builds, which
+// would otherwise each open their own connections
+var defaultTransport = newTransport()
+
+// defaultClient returns DefaultClient, or a new client from the environment when it is unset
+func defaultClient() *Client {
+	defaultMu.RLock()
+	c := DefaultClient
+	defaultMu.RUnlock()
+	if c == nil {
+		return NewClientWithOptions(withDefaultTransport())
+	}
+	return c
+}
+
+// withDefaultTransport makes the client send through defaultTransport
+func withDefaultTransport() Option {
+	return func(c *Client) {
+		hc := *c.httpClient()
+		hc.Transport = defaultTransport
+		c.HTTPClient = &hc
+	}
+}
@@ -0,0 +1,52 @@
+package medic
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetDefaultClient(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	SetDefaultClient(NewClientWithOptions(WithBaseURL(srv.URL), WithBearerToken("startup-token")))
+	defer SetDefaultClient(nil)
+
+	if err := SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if auth != "Bearer startup-token" {
+		t.Errorf("Authorization = %q, want the default client's token", auth)
+	}
+}
+
+func TestDefaultClientReusesConnections(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	var conns atomic.Int32
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+	t.Setenv("MEDIC_BASE_URL", srv.URL)
+
+	for range 5 {
+		if err := SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+	}
+	if got := conns.Load(); got != 1 {
+		t.Errorf("connections = %d, want 1 reused across package-level sends", got)
+	}
+}
@@ -0,0 +1,55 @@
+package medic
+
+import "net/http"
+
+// Doer sends an HTTP request and returns its response. *http.Client
+// satisfies it, as can instrumented wrappers and test doubles
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WithHTTPDoer sends every request through d instead of the client's
+// HTTPClient. Transport options such as WithProxy, WithTimeout, and
+// WithTransportMiddleware configure HTTPClient, so they do not apply to d
+func WithHTTPDoer(d Doer) Option {
+	return func(c *Client) {
+		c.Doer = d
+	}
+}
+
+// doer returns the client's Doer, or its HTTP client when none is set
+func (c *Client) doer() Doer {
+	if c.Doer != nil {
+		return c.Doer
+	}
+	hc := c.httpClient()
+	if c.SuccessPredicate != nil && hc.CheckRedirect == nil {
+		// Hand the predicate the 3xx instead of following it
+		noRedirect := *hc
+		noRedirect.CheckRedirect = useLastResponse
+		return &noRedirect
+	}
+	return hc
+}
+
+// requestDoer returns the Doer for a call configured by rc. A call with its
+// own WithRequestTimeout is bounded by its context alone, so that timeout can
+// be longer as well as shorter than HTTPClient.Timeout
+func (c *Client) requestDoer(rc *requestConfig) Doer {
+	if rc.timeout > 0 {
+		return c.unboundedDoer()
+	}
+	return c.doer()
+}
+
+// unboundedDoer returns the client's Doer without the overall request timeout
+// of an *http.Client, for requests bounded some other way
+func (c *Client) unboundedDoer() Doer {
+	d := c.doer()
+	if hc, ok := d.(*http.Client); ok && hc.Timeout > 0 {
+		unbounded := *hc
+		unbounded.Timeout = 0
+		return &unbounded
+	}
+	return d
+}
@@ -0,0 +1,74 @@
+package medic
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// WithDryRun makes the client validate and build every request, including
+// headers and body, then log it and report success without contacting Medic.
+// Use it in local development and tests that must not have side effects
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.DryRun = true
+	}
+}
+
+// dryRun builds the request do would send and logs it in place of sending.
+// The response is an empty success envelope with StatusCode 0, since no
+// server answered
+func (c *Client) dryRun(ctx context.Context, method, url string, payload []byte, label string, rc *requestConfig) (*response, attemptResult, error) {
+	req, err := c.newRequest(ctx, method, url, payload, rc)
+	if err != nil {
+		return nil, attemptResult{}, err
+	}
+	body := string(payload)
+	if rc.contentEncoding != "" {
+		body = fmt.Sprintf("<%d bytes, %s>", len(payload), rc.contentEncoding)
+	} else if payload != nil && c.Encoding == EncodingProtobuf {
+		body = fmt.Sprintf("<%d bytes, %s>", len(payload), c.Encoding)
+	}
+	headers := redactHeaders(req.Header)
+
+	if c.Logger == nil {
+		log.Printf("Dry run: would %s heartbeat to Medic: %s %s, Headers: %v, Body: %s, Heartbeat: %s",
+			verb(method), method, req.URL, headers, strings.TrimSpace(body), label)
+	} else {
+		c.Logger.Info("Dry run: would "+verb(method)+" heartbeat to Medic",
+			slog.String("heartbeat_name", label),
+			slog.String("method", method),
+			slog.String("url", req.URL.String()),
+			slog.Any("headers", headers),
+			slog.String("body", strings.TrimSpace(body)),
+		)
+	}
+	return &response{Header: http.Header{}, Body: dryRunBody}, attemptResult{}, nil
+}
+
+// dryRunBody is the envelope returned for every dry-run request
+var dryRunBody = []byte(`{"success":true,"message":"dry run","results":""}`)
+
+// sensitiveHeaderWords mark headers whose values are kept out of logs
+var sensitiveHeaderWords = []string{"authorization", "token", "secret", "api-key", "apikey", "signature", "cookie"}
+
+// redacted replaces the values of credential-bearing headers
+const redacted = "REDACTED"
+
+// redactHeaders copies h, replacing the values of credential-bearing headers
+func redactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for key := range out {
+		lower := strings.ToLower(key)
+		for _, word := range sensitiveHeaderWords {
+			if strings.Contains(lower, word) {
+				out[key] = []string{redacted}
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithDryRun(t *testing.T) {
+	hit := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithDryRun(),
+		WithBearerToken("s3cret"),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if hit {
+		t.Error("dry run contacted the server")
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log output %q is not a single JSON entry: %v", buf.String(), err)
+	}
+	if entry["url"] != srv.URL+"/heartbeat" || !strings.Contains(entry["body"].(string), "staging-fake-heartbeat-hb") {
+		t.Errorf("log entry = %v, want the request URL and body", entry)
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Errorf("dry run log leaked the bearer token: %s", buf.String())
+	}
+
+	// Validation still runs
+	if err := c.SendHeartbeat(Heartbeat{}); err == nil {
+		t.Error("SendHeartbeat() with no name expected a validation error")
+	}
+}
+
+func TestDryRunLookup(t *testing.T) {
+	c := NewClientWithOptions(WithDryRun(), WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if _, err := c.GetHeartbeat(context.Background(), "staging-fake-heartbeat-hb"); !errors.Is(err, ErrHeartbeatNotFound) {
+		t.Errorf("GetHeartbeat() error = %v, want ErrHeartbeatNotFound", err)
+	}
+	hs, err := c.ListHeartbeats(context.Background(), "fakeservice")
+	if err != nil || len(hs) != 0 {
+		t.Errorf("ListHeartbeats() = %v, %v, want no heartbeats", hs, err)
+	}
+}
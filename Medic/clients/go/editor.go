@@ -0,0 +1,29 @@
+package medic
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RequestEditor mutates a fully built request just before it is sent, for
+// one-off needs such as a body signature or an extra header. An error aborts
+// the send without retrying
+type RequestEditor func(req *http.Request) error
+
+// WithRequestEditor adds editors that run, in registration order, on every
+// request attempt after all headers are set. Dry runs log the edited request
+func WithRequestEditor(editors ...RequestEditor) Option {
+	return func(c *Client) {
+		c.requestEditors = append(c.requestEditors, editors...)
+	}
+}
+
+// editRequest runs the client's request editors on req in order
+func (c *Client) editRequest(req *http.Request) error {
+	for _, edit := range c.requestEditors {
+		if err := edit(req); err != nil {
+			return fmt.Errorf("request editor failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package medic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/linq-team/medic/Medic/clients/go/internal/medicpb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/linq-team/medic/Medic/clients/go heartbeat.proto
+
+// Encoding is the wire format of request bodies
+type Encoding int
+
+const (
+	// EncodingJSON sends application/json bodies, and application/x-ndjson
+	// streams. It is the default
+	EncodingJSON Encoding = iota
+	// EncodingProtobuf sends application/x-protobuf bodies using the
+	// messages in heartbeat.proto. Streams send each Heartbeat message
+	// prefixed with its varint length
+	EncodingProtobuf
+)
+
+// String returns the encoding's name, such as "json"
+func (e Encoding) String() string {
+	switch e {
+	case EncodingJSON:
+		return "json"
+	case EncodingProtobuf:
+		return "protobuf"
+	default:
+		return fmt.Sprintf("Encoding(%d)", int(e))
+	}
+}
+
+// WithEncoding sends single heartbeats, batches, and streams in encoding e.
+// Responses are still read as JSON. A Marshaler, if set, still builds single
+// heartbeat bodies, which are labelled with e's content type
+func WithEncoding(e Encoding) Option {
+	return func(c *Client) {
+		if e != EncodingJSON && e != EncodingProtobuf {
+			c.setConfigErr(fmt.Errorf("unknown encoding %v", e))
+			return
+		}
+		c.Encoding = e
+	}
+}
+
+// contentType returns the Content-Type of request bodies
+func (c *Client) contentType() string {
+	if c.Encoding == EncodingProtobuf {
+		return "application/x-protobuf"
+	}
+	return "application/json"
+}
+
+// streamContentType returns the Content-Type of heartbeat streams
+func (c *Client) streamContentType() string {
+	if c.Encoding == EncodingProtobuf {
+		return "application/x-protobuf; delimited=true"
+	}
+	return "application/x-ndjson"
+}
+
+// marshalBatch builds the request body for a batch of heartbeats
+func (c *Client) marshalBatch(hs []Heartbeat) ([]byte, error) {
+	if c.Encoding == EncodingProtobuf {
+		batch := &medicpb.HeartbeatBatch{Heartbeats: make([]*medicpb.Heartbeat, len(hs))}
+		for i, h := range hs {
+			batch.Heartbeats[i] = heartbeatProto(h)
+		}
+		return protoMarshal.Marshal(batch)
+	}
+	if len(c.WireProfile.keys) > 0 {
+		objs := make([]json.RawMessage, len(hs))
+		for i, h := range hs {
+			obj, err := c.marshalJSON(h)
+			if err != nil {
+				return nil, err
+			}
+			objs[i] = obj
+		}
+		return json.Marshal(objs)
+	}
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(hs); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// encodeStreamRecord renders one marshaled heartbeat as a record of the stream
+func (c *Client) encodeStreamRecord(body []byte) []byte {
+	if c.Encoding == EncodingProtobuf {
+		return protowire.AppendBytes(nil, body)
+	}
+	// One heartbeat per line, whatever trailing newline the encoder wrote
+	return append(bytes.TrimRight(body, "\r\n"), '\n')
+}
+
+// protoMarshal sorts map entries so protobuf bodies are deterministic
+var protoMarshal = proto.MarshalOptions{Deterministic: true}
+
+// heartbeatProto converts h to the medic.v1.Heartbeat message. Extra is not
+// sent
+func heartbeatProto(h Heartbeat) *medicpb.Heartbeat {
+	m := &medicpb.Heartbeat{
+		HeartbeatName:   h.HeartbeatName,
+		ServiceName:     h.Service,
+		Status:          string(h.Status),
+		Metadata:        h.Metadata,
+		IntervalSeconds: h.Interval.Seconds(),
+		Severity:        string(h.Severity),
+		ServiceNames:    h.Services,
+	}
+	if !h.Timestamp.IsZero() {
+		m.Timestamp = timestamppb.New(h.Timestamp)
+	}
+	return m
+}
@@ -0,0 +1,152 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linq-team/medic/Medic/clients/go/internal/medicpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// decodeProto decodes body as the named message and renders it as generic
+// JSON with proto field names
+func decodeProto(t *testing.T, file protoreflect.FileDescriptor, name protoreflect.Name, body []byte) any {
+	t.Helper()
+	m := dynamicpb.NewMessage(file.Messages().ByName(name))
+	if err := proto.Unmarshal(body, m); err != nil {
+		t.Fatalf("body is not a valid %s: %v", name, err)
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		t.Fatalf("protojson.Marshal() unexpected error = %v", err)
+	}
+	var v any
+	json.Unmarshal(data, &v)
+	return v
+}
+
+func TestWithEncodingProtobuf(t *testing.T) {
+	file := medicpb.File_heartbeat_proto
+	h := Heartbeat{
+		HeartbeatName: "staging-fake-heartbeat-hb",
+		Service:       "fakeservice",
+		Status:        StatusUp,
+		Severity:      SeverityCritical,
+		Services:      []string{"billing", "invoices"},
+		Metadata:      map[string]string{"region": "eu", "version": "1.2"},
+		Interval:      30 * time.Second,
+		Timestamp:     time.Date(2024, 1, 1, 12, 0, 0, 5, time.UTC),
+	}
+	want := map[string]any{
+		"heartbeat_name":   "staging-fake-heartbeat-hb",
+		"service_name":     "fakeservice",
+		"status":           "UP",
+		"severity":         "CRITICAL",
+		"service_names":    []any{"billing", "invoices"},
+		"metadata":         map[string]any{"region": "eu", "version": "1.2"},
+		"interval_seconds": float64(30),
+		"timestamp":        "2024-01-01T12:00:00.000000005Z",
+	}
+
+	type request struct {
+		path, contentType string
+		body              []byte
+	}
+	got := make(chan request, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- request{path: r.URL.Path, contentType: r.Header.Get("Content-Type"), body: body}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithEncoding(EncodingProtobuf))
+
+	tests := []struct {
+		name            string
+		send            func() error
+		wantContentType string
+		check           func(t *testing.T, body []byte)
+	}{
+		{
+			name:            "testing single heartbeat",
+			send:            func() error { return c.SendHeartbeat(h) },
+			wantContentType: "application/x-protobuf",
+			check: func(t *testing.T, body []byte) {
+				if msg := decodeProto(t, file, "Heartbeat", body); !reflect.DeepEqual(msg, any(want)) {
+					t.Errorf("Heartbeat = %v, want %v", msg, want)
+				}
+			},
+		},
+		{
+			name:            "testing batch",
+			send:            func() error { return c.SendHeartbeats([]Heartbeat{h, h}) },
+			wantContentType: "application/x-protobuf",
+			check: func(t *testing.T, body []byte) {
+				msg := decodeProto(t, file, "HeartbeatBatch", body)
+				if wantBatch := map[string]any{"heartbeats": []any{want, want}}; !reflect.DeepEqual(msg, any(wantBatch)) {
+					t.Errorf("HeartbeatBatch = %v, want %v", msg, wantBatch)
+				}
+			},
+		},
+		{
+			name: "testing stream",
+			send: func() error {
+				beats, errs := c.StreamHeartbeats(context.Background())
+				beats <- h
+				beats <- h
+				close(beats)
+				for err := range errs {
+					return err
+				}
+				return nil
+			},
+			wantContentType: "application/x-protobuf; delimited=true",
+			check: func(t *testing.T, body []byte) {
+				records := 0
+				for len(body) > 0 {
+					record, n := protowire.ConsumeBytes(body)
+					if n < 0 {
+						t.Fatalf("stream record %d is not length-delimited", records)
+					}
+					if msg := decodeProto(t, file, "Heartbeat", record); !reflect.DeepEqual(msg, any(want)) {
+						t.Errorf("record %d = %v, want %v", records, msg, want)
+					}
+					body = body[n:]
+					records++
+				}
+				if records != 2 {
+					t.Errorf("stream had %d records, want 2", records)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.send(); err != nil {
+				t.Fatalf("send unexpected error = %v", err)
+			}
+			req := <-got
+			if req.contentType != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", req.contentType, tt.wantContentType)
+			}
+			tt.check(t, req.body)
+		})
+	}
+
+	t.Run("testing unknown encoding", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithEncoding(Encoding(7)))
+		if err := c.SendHeartbeat(h); err == nil {
+			t.Error("SendHeartbeat() expected a configuration error")
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package medic
+
+import (
+	"os"
+	"strings"
+)
+
+// NewClientFromEnv creates a client configured from environment variables
+// namespaced by prefix, so one process can talk to several Medic deployments.
+// With prefix "STAGING" it reads STAGING_MEDIC_BASE_URL,
+// STAGING_MEDIC_API_TOKEN and STAGING_MEDIC_TIMEOUT, falling back to the
+// unprefixed MEDIC_BASE_URL, MEDIC_API_TOKEN and MEDIC_TIMEOUT when a prefixed
+// variable is unset. opts are applied after the environment, so they take
+// precedence. A malformed timeout is a configuration error, returned by every
+// request
+func NewClientFromEnv(prefix string, opts ...Option) *Client {
+	envOpts := []Option{WithBaseURL(lookupEnv(prefix, "MEDIC_BASE_URL"))}
+	if token := lookupEnv(prefix, "MEDIC_API_TOKEN"); token != "" {
+		envOpts = append(envOpts, WithBearerToken(token))
+	}
+	switch d, err := parseTimeout(lookupEnv(prefix, "MEDIC_TIMEOUT")); {
+	case err != nil:
+		envOpts = append(envOpts, func(c *Client) { c.setConfigErr(err) })
+	case d > 0:
+		envOpts = append(envOpts, WithTimeout(d))
+	}
+	return NewClientWithOptions(append(envOpts, opts...)...)
+}
+
+// lookupEnv returns <PREFIX>_<name>, or name when the prefixed variable is
+// unset or prefix is empty
+func lookupEnv(prefix, name string) string {
+	if prefix = strings.TrimSuffix(strings.ToUpper(prefix), "_"); prefix != "" {
+		if v := os.Getenv(prefix + "_" + name); v != "" {
+			return v
+		}
+	}
+	return os.Getenv(name)
+}
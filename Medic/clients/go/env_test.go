@@ -0,0 +1,117 @@
+package medic
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv("MEDIC_BASE_URL", "https://medic.example.com")
+	t.Setenv("MEDIC_API_TOKEN", "default-token")
+	t.Setenv("STAGING_MEDIC_BASE_URL", "https://medic.staging.example.com/")
+	t.Setenv("PROD_MEDIC_API_TOKEN", "prod-token")
+
+	tests := []struct {
+		name      string
+		prefix    string
+		opts      []Option
+		wantURL   string
+		wantToken string
+	}{
+		{name: "testing prefixed base URL", prefix: "STAGING", wantURL: "https://medic.staging.example.com", wantToken: "default-token"},
+		{name: "testing prefixed token", prefix: "prod", wantURL: "https://medic.example.com", wantToken: "prod-token"},
+		{name: "testing no prefix", wantURL: "https://medic.example.com", wantToken: "default-token"},
+		{name: "testing option overrides env", prefix: "STAGING_", opts: []Option{WithBaseURL("https://other.example.com")}, wantURL: "https://other.example.com", wantToken: "default-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientFromEnv(tt.prefix, tt.opts...)
+			if c.BaseURL != tt.wantURL || c.AuthToken != tt.wantToken {
+				t.Errorf("NewClientFromEnv(%q) = %q, %q, want %q, %q", tt.prefix, c.BaseURL, c.AuthToken, tt.wantURL, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestGetTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{name: "testing unset", want: DefaultTimeout},
+		{name: "testing duration", env: "2s", want: 2 * time.Second},
+		{name: "testing surrounding whitespace", env: " 1500ms ", want: 1500 * time.Millisecond},
+		{name: "testing malformed value", env: "2", want: DefaultTimeout},
+		{name: "testing negative value", env: "-5s", want: DefaultTimeout},
+		{name: "testing zero", env: "0s", want: DefaultTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MEDIC_TIMEOUT", tt.env)
+			if got := GetTimeout(); got != tt.want {
+				t.Errorf("GetTimeout() = %v, want %v", got, tt.want)
+			}
+			if got := NewClient("").HTTPClient.Timeout; got != tt.want {
+				t.Errorf("NewClient() timeout = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("testing option overrides env", func(t *testing.T) {
+		t.Setenv("MEDIC_TIMEOUT", "2s")
+		if got := NewClientWithOptions(WithTimeout(5 * time.Second)).HTTPClient.Timeout; got != 5*time.Second {
+			t.Errorf("Timeout = %v, want 5s", got)
+		}
+	})
+
+	t.Run("testing prefixed env", func(t *testing.T) {
+		t.Setenv("MEDIC_TIMEOUT", "2s")
+		t.Setenv("STAGING_MEDIC_TIMEOUT", "750ms")
+		if got := NewClientFromEnv("STAGING").HTTPClient.Timeout; got != 750*time.Millisecond {
+			t.Errorf("NewClientFromEnv(STAGING) timeout = %v, want 750ms", got)
+		}
+		if got := NewClientFromEnv("PROD").HTTPClient.Timeout; got != 2*time.Second {
+			t.Errorf("NewClientFromEnv(PROD) timeout = %v, want 2s", got)
+		}
+	})
+}
+
+func TestMalformedTimeoutEnv(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	t.Run("testing GetTimeout logs once", func(t *testing.T) {
+		t.Setenv("MEDIC_TIMEOUT", "abc")
+		loggedTimeoutErrs.Clear()
+		for range 3 {
+			if got := GetTimeout(); got != DefaultTimeout {
+				t.Errorf("GetTimeout() = %v, want %v", got, DefaultTimeout)
+			}
+		}
+		if got := strings.Count(buf.String(), `invalid MEDIC_TIMEOUT "abc"`); got != 1 {
+			t.Errorf("logged the malformed timeout %d times, want 1; log: %s", got, buf.String())
+		}
+	})
+
+	t.Run("testing NewClientStrict fails", func(t *testing.T) {
+		t.Setenv("MEDIC_TIMEOUT", "2")
+		if _, err := NewClientStrict("https://medic.example.com"); err == nil || !strings.Contains(err.Error(), "invalid MEDIC_TIMEOUT") {
+			t.Errorf("NewClientStrict() error = %v, want invalid MEDIC_TIMEOUT", err)
+		}
+	})
+
+	t.Run("testing NewClientFromEnv config error", func(t *testing.T) {
+		t.Setenv("STAGING_MEDIC_TIMEOUT", "-1s")
+		c := NewClientFromEnv("STAGING", WithBaseURL("https://medic.example.com"))
+		err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+		if err == nil || !strings.Contains(err.Error(), `invalid MEDIC_TIMEOUT "-1s"`) {
+			t.Errorf("SendHeartbeat() error = %v, want the malformed timeout", err)
+		}
+	})
+}
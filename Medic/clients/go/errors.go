@@ -0,0 +1,166 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrClientError and ErrServerError classify a *StatusError by status class:
+// errors.Is(err, ErrClientError) is true for a 4xx, and ErrServerError for a 5xx
+var (
+	ErrClientError = errors.New("medic client error")
+	ErrServerError = errors.New("medic server error")
+)
+
+// StatusError is returned when Medic responds with an unsuccessful status code
+type StatusError struct {
+	StatusCode int
+	// Message is the explanation from the server's response envelope, if any
+	Message string
+	// Body is the raw response body
+	Body []byte
+	// RequestID is the server's X-Request-ID for the response, if sent
+	RequestID string
+	// Headers holds the response headers named by WithCapturedHeaders, and
+	// X-Request-ID, that the server sent
+	Headers http.Header
+}
+
+// newStatusError builds a StatusError, extracting the message from a Medic response envelope
+func newStatusError(code int, body []byte) *StatusError {
+	e := &StatusError{StatusCode: code, Body: body}
+	var envelope struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		e.Message = envelope.Message
+	}
+	return e
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// Is matches ErrClientError for 4xx codes and ErrServerError for 5xx codes
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrClientError:
+		return e.StatusCode >= 400 && e.StatusCode < 500
+	case ErrServerError:
+		return e.StatusCode >= 500 && e.StatusCode < 600
+	}
+	return false
+}
+
+// ContentTypeError is returned when a successful response is not JSON, which
+// usually means a reverse proxy answered in place of Medic
+type ContentTypeError struct {
+	StatusCode  int
+	ContentType string
+	// Snippet is the start of the response body
+	Snippet string
+}
+
+func (e *ContentTypeError) Error() string {
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "untyped"
+	}
+	return fmt.Sprintf("unexpected %s response with status %d, not JSON from Medic: %q", contentType, e.StatusCode, e.Snippet)
+}
+
+// ValidationError is returned when a heartbeat fails validation before sending
+type ValidationError struct {
+	// Field is the JSON name of the first offending field
+	Field string
+	// Message describes the problem, e.g. "is required"
+	Message string
+	// Fields lists every violation found when validating a heartbeat, in
+	// field order, starting with the one in Field and Message
+	Fields []FieldError
+}
+
+// FieldError is a single field's validation failure
+type FieldError struct {
+	// Field is the JSON name of the offending field
+	Field string
+	// Message describes the problem, e.g. "is required"
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// newValidationError reports fields, which must not be empty
+func newValidationError(fields []FieldError) *ValidationError {
+	return &ValidationError{Field: fields[0].Field, Message: fields[0].Message, Fields: fields}
+}
+
+// Error describes every violation, separated by semicolons
+func (e *ValidationError) Error() string {
+	if len(e.Fields) <= 1 {
+		return fmt.Sprintf("%s %s", e.Field, e.Message)
+	}
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Error()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// TransportError is returned when a request could not be completed, such as
+// on connection failures, timeouts, or context cancellation. Err keeps the
+// underlying error, so errors.As finds a net.Error or *net.OpError in it
+type TransportError struct {
+	// Method and Path identify the request, such as GET /heartbeat/{name}
+	Method string
+	Path   string
+	Err    error
+}
+
+// newTransportError reports err for the request method sends to rawURL
+func newTransportError(method, rawURL string, err error) *TransportError {
+	path := rawURL
+	if u, parseErr := url.Parse(rawURL); parseErr == nil {
+		path = u.Path
+	}
+	return &TransportError{Method: method, Path: path, Err: err}
+}
+
+func (e *TransportError) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("medic request failed: %v", e.Err)
+	}
+	return fmt.Sprintf("medic %s %s failed: %v", e.Method, e.Path, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// IsTimeout reports whether err, anywhere in its chain, is a timeout: a
+// net.Error whose Timeout method returns true, such as HTTPClient.Timeout
+// or a dial timeout, or a context deadline. A refused connection or a DNS
+// failure is not a timeout
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
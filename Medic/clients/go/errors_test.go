@@ -0,0 +1,135 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	t.Run("testing status error", func(t *testing.T) {
+		err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"})
+		var se *StatusError
+		if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+			t.Fatalf("SendHeartbeat() error = %v, want *StatusError with 404", err)
+		}
+		if err.Error() != "unexpected status code 404" {
+			t.Errorf("Error() = %q, want unchanged message", err.Error())
+		}
+	})
+
+	t.Run("testing validation error", func(t *testing.T) {
+		err := c.SendHeartbeat(Heartbeat{Status: "UP"})
+		var ve *ValidationError
+		if !errors.As(err, &ve) || ve.Field != "heartbeat_name" {
+			t.Fatalf("SendHeartbeat() error = %v, want *ValidationError for heartbeat_name", err)
+		}
+	})
+
+	t.Run("testing transport error", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+		c := NewClient(closed.URL)
+		c.Retry.MaxAttempts = 1
+		err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"})
+		var te *TransportError
+		if !errors.As(err, &te) {
+			t.Fatalf("SendHeartbeat() error = %v, want *TransportError", err)
+		}
+		if te.Method != http.MethodPost || te.Path != "/heartbeat" || !strings.HasPrefix(te.Error(), "medic POST /heartbeat failed: ") {
+			t.Errorf("TransportError = %s %s %q, want the heartbeat post", te.Method, te.Path, te.Error())
+		}
+	})
+}
+
+func TestStatusClassSentinels(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       int
+		wantClient bool
+		wantServer bool
+	}{
+		{name: "testing 400", code: http.StatusBadRequest, wantClient: true},
+		{name: "testing 429", code: http.StatusTooManyRequests, wantClient: true},
+		{name: "testing 500", code: http.StatusInternalServerError, wantServer: true},
+		{name: "testing 503", code: http.StatusServiceUnavailable, wantServer: true},
+		{name: "testing 302", code: http.StatusFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+			}))
+			defer srv.Close()
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithRetry(RetryConfig{MaxAttempts: 2}), WithClock(newFakeClock()))
+			err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"})
+			if got := errors.Is(err, ErrClientError); got != tt.wantClient {
+				t.Errorf("errors.Is(%v, ErrClientError) = %v, want %v", err, got, tt.wantClient)
+			}
+			if got := errors.Is(err, ErrServerError); got != tt.wantServer {
+				t.Errorf("errors.Is(%v, ErrServerError) = %v, want %v", err, got, tt.wantServer)
+			}
+		})
+	}
+}
+
+func TestIsTimeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer slow.Close()
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	noRetry := WithRetry(RetryConfig{MaxAttempts: 1})
+	tests := []struct {
+		name       string
+		opts       []Option
+		reqOpts    []RequestOption
+		wantNetErr bool
+		want       bool
+	}{
+		{name: "testing client timeout", opts: []Option{WithBaseURL(slow.URL), WithTimeout(20 * time.Millisecond), noRetry}, wantNetErr: true, want: true},
+		{name: "testing timeout after retries", opts: []Option{WithBaseURL(slow.URL), WithTimeout(20 * time.Millisecond), WithRetry(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond})}, wantNetErr: true, want: true},
+		{name: "testing request timeout", opts: []Option{WithBaseURL(slow.URL), noRetry}, reqOpts: []RequestOption{WithRequestTimeout(20 * time.Millisecond)}, wantNetErr: true, want: true},
+		{name: "testing connection refused", opts: []Option{WithBaseURL(closedURL), noRetry}, wantNetErr: true},
+		{name: "testing status error", opts: []Option{WithBaseURL(notFound.URL), noRetry}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientWithOptions(tt.opts...)
+			err := c.SendHeartbeatContext(context.Background(), h, tt.reqOpts...)
+			if err == nil {
+				t.Fatal("SendHeartbeatContext() expected an error")
+			}
+			var netErr net.Error
+			if got := errors.As(err, &netErr); got != tt.wantNetErr {
+				t.Errorf("errors.As(%v, net.Error) = %v, want %v", err, got, tt.wantNetErr)
+			}
+			if got := IsTimeout(err); got != tt.want {
+				t.Errorf("IsTimeout(%v) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package medic
+
+import "context"
+
+// SendHeartbeatOrLog sends a heartbeat post to medic using the default
+// client, logging any failure instead of returning it. See
+// Client.SendHeartbeatOrLog
+func SendHeartbeatOrLog(h Heartbeat) {
+	defaultClient().SendHeartbeatOrLog(h)
+}
+
+// SendHeartbeatOrLog sends a heartbeat post to medic and fails open: any
+// error, including an invalid heartbeat, is logged once and swallowed, so a
+// Medic problem can never fail the caller. Use SendHeartbeat instead when a
+// failed heartbeat should abort the surrounding operation
+func (c *Client) SendHeartbeatOrLog(h Heartbeat) {
+	if err := c.SendHeartbeatContext(context.Background(), h); err != nil {
+		c.logDropped(h.HeartbeatName, err)
+	}
+}
@@ -0,0 +1,36 @@
+package medic
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSendHeartbeatOrLog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		h       Heartbeat
+		wantLog string
+	}{
+		{name: "testing failed send is logged", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}, wantLog: "unexpected status code 404"},
+		{name: "testing invalid heartbeat is logged", h: Heartbeat{Status: StatusUp}, wantLog: "invalid heartbeat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+			c.SendHeartbeatOrLog(tt.h)
+			if out := buf.String(); !strings.Contains(out, "Ignoring failed heartbeat") || !strings.Contains(out, tt.wantLog) {
+				t.Errorf("log output = %q, want the ignored error %q", out, tt.wantLog)
+			}
+		})
+	}
+}
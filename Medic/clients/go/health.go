@@ -0,0 +1,28 @@
+package medic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Ping checks that the Medic server is reachable and healthy by calling its
+// /health endpoint. It makes a single attempt without retrying, so startup
+// probes fail fast, and returns nil only on a 2xx response
+func (c *Client) Ping(ctx context.Context) (err error) {
+	ctx, end := c.startSpan(ctx, "medic.Ping")
+	var resp *response
+	defer func() { end(resp, err) }()
+
+	if c.configErr != nil {
+		return fmt.Errorf("invalid client configuration: %w", c.configErr)
+	}
+	if err := c.checkClosed(); err != nil {
+		return err
+	}
+	resp, _, err = c.do(ctx, http.MethodGet, c.baseURL(nil)+"/health", nil, "health check", newRequestConfig(nil))
+	if err != nil {
+		return fmt.Errorf("medic health check failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "testing healthy", status: http.StatusOK},
+		{name: "testing unhealthy", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				if r.Method != http.MethodGet || r.URL.Path != "/health" {
+					t.Errorf("got %s %s, want GET /health", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := NewClient(srv.URL).Ping(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var se *StatusError
+			if tt.wantErr && (!errors.As(err, &se) || se.StatusCode != tt.status) {
+				t.Errorf("Ping() error = %v, want *StatusError with code %d", err, tt.status)
+			}
+			if n := atomic.LoadInt32(&calls); n != 1 {
+				t.Errorf("server called %d times, want 1 (no retries)", n)
+			}
+		})
+	}
+
+	t.Run("testing unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+		var te *TransportError
+		if err := NewClient(srv.URL).Ping(context.Background()); !errors.As(err, &te) || te.Method != http.MethodGet || te.Path != "/health" {
+			t.Errorf("Ping() error = %v, want *TransportError for GET /health", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// HeartbeatEvent is one entry in a heartbeat's status history
+type HeartbeatEvent struct {
+	// Time is when the event was recorded
+	Time   time.Time
+	Status Status
+	// Metadata is the context sent with the heartbeat, if any
+	Metadata map[string]string
+}
+
+// GetHeartbeatHistory returns the events recorded for the named heartbeat
+// since the given time, newest first, from GET /heartbeat/{name}/events. A
+// zero since returns the full history the server retains. It returns
+// ErrHeartbeatNotFound if the heartbeat was never registered
+func (c *Client) GetHeartbeatHistory(ctx context.Context, name string, since time.Time, opts ...RequestOption) ([]HeartbeatEvent, error) {
+	if name == "" {
+		return nil, &ValidationError{Field: "heartbeat_name", Message: "is required"}
+	}
+
+	rc := newRequestConfig(opts)
+	endpoint := fmt.Sprintf("%s/heartbeat/%s/events", c.baseURL(rc), url.PathEscape(name))
+	if !since.IsZero() {
+		endpoint += "?" + url.Values{"since": {since.UTC().Format(time.RFC3339)}}.Encode()
+	}
+	resp, err := c.doWithRetry(ctx, http.MethodGet, endpoint, nil, name, rc)
+	var se *StatusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrHeartbeatNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, records, err := decodeRecords(resp.Body, c.WireProfile)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]HeartbeatEvent, len(records))
+	for i, r := range records {
+		events[i] = HeartbeatEvent{Status: r.Status, Metadata: r.Metadata}
+		if t, ok := parseServerTime(r.Time); ok {
+			events[i].Time = t
+		} else if t, ok := parseServerTime(r.LastSeen); ok {
+			events[i].Time = t
+		}
+	}
+	// The server's order is unspecified, so sort here
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.After(events[j].Time)
+	})
+	return events, nil
+}
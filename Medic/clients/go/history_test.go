@@ -0,0 +1,53 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetHeartbeatHistory(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/heartbeat/missing-hb/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path != "/heartbeat/staging-fake-heartbeat-hb/events" {
+			t.Errorf("path = %q, want /heartbeat/staging-fake-heartbeat-hb/events", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("since"); got != "2024-01-01T00:00:00Z" {
+			t.Errorf("since = %q, want 2024-01-01T00:00:00Z", got)
+		}
+		w.Write([]byte(`{"success":true,"message":"","results":[
+			{"status":"UP","time":"2024-01-01 10:00:00.000000"},
+			{"status":"DOWN","time":"2024-01-01T11:00:00Z","metadata":{"region":"us-east-1"}},
+			{"status":"UP","time":"2024-01-01T12:00:00Z"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events, err := c.GetHeartbeatHistory(context.Background(), "staging-fake-heartbeat-hb", since)
+	if err != nil {
+		t.Fatalf("GetHeartbeatHistory() unexpected error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("GetHeartbeatHistory() returned %d events, want 3", len(events))
+	}
+	for i, hour := range []int{12, 11, 10} {
+		if events[i].Time.Hour() != hour {
+			t.Errorf("events[%d].Time = %v, want hour %d (newest first)", i, events[i].Time, hour)
+		}
+	}
+	if events[1].Status != StatusDown || events[1].Metadata["region"] != "us-east-1" {
+		t.Errorf("events[1] = %+v, want DOWN with region metadata", events[1])
+	}
+
+	if _, err := c.GetHeartbeatHistory(context.Background(), "missing-hb", since); !errors.Is(err, ErrHeartbeatNotFound) {
+		t.Errorf("GetHeartbeatHistory() error = %v, want ErrHeartbeatNotFound", err)
+	}
+}
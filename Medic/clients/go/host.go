@@ -0,0 +1,91 @@
+package medic
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// HostOption configures how HeartbeatForHost builds a heartbeat
+type HostOption func(*hostConfig)
+
+// hostConfig holds the options for one HeartbeatForHost call
+type hostConfig struct {
+	name func(service, host string) string
+}
+
+// WithHostNameFormat builds the heartbeat name from the service and the
+// sanitized host or pod name with format, instead of "<service>-<host>"
+func WithHostNameFormat(format func(service, host string) string) HostOption {
+	return func(hc *hostConfig) {
+		if format != nil {
+			hc.name = format
+		}
+	}
+}
+
+// hostHeartbeatName is the default HeartbeatForHost naming convention
+func hostHeartbeatName(service, host string) string {
+	return service + "-" + host
+}
+
+// HeartbeatForHost returns a heartbeat for service on this instance, named
+// "<service>-<host>" unless WithHostNameFormat is given. The instance is the
+// POD_NAME environment variable, then HOSTNAME, then os.Hostname. Characters
+// outside the default name pattern, in the instance and in the final name,
+// are replaced by "-". The heartbeat is validated before it is returned
+func HeartbeatForHost(service string, status Status, opts ...HostOption) (Heartbeat, error) {
+	hc := hostConfig{name: hostHeartbeatName}
+	for _, opt := range opts {
+		opt(&hc)
+	}
+	host, err := instanceName()
+	if err != nil {
+		return Heartbeat{}, err
+	}
+	h := Heartbeat{
+		HeartbeatName: sanitizeName(hc.name(service, host)),
+		Service:       service,
+		Status:        status,
+	}
+	if err := h.Validate(); err != nil {
+		return Heartbeat{}, err
+	}
+	return h, nil
+}
+
+// instanceName returns the sanitized pod or host name of this instance
+func instanceName() (string, error) {
+	for _, key := range []string{"POD_NAME", "HOSTNAME"} {
+		if v := sanitizeName(os.Getenv(key)); v != "" {
+			return v, nil
+		}
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine hostname: %w", err)
+	}
+	name := sanitizeName(host)
+	if name == "" {
+		return "", fmt.Errorf("hostname %q has no usable characters", host)
+	}
+	return name, nil
+}
+
+// sanitizeName replaces runs of characters not allowed by DefaultNamePattern
+// with a single "-", and trims leading and trailing dashes
+func sanitizeName(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+			dash = false
+		case !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
@@ -0,0 +1,46 @@
+package medic
+
+import "testing"
+
+func TestHeartbeatForHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		podName  string
+		hostname string
+		want     string
+	}{
+		{name: "testing pod name wins", podName: "api-7d9f8-x2k", hostname: "node-1", want: "fakeservice-api-7d9f8-x2k"},
+		{name: "testing hostname", hostname: "web01.prod.example.com", want: "fakeservice-web01-prod-example-com"},
+		{name: "testing unusual characters", hostname: "--ip 10.0.0.1 (eu)--", want: "fakeservice-ip-10-0-0-1-eu"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAME", tt.podName)
+			t.Setenv("HOSTNAME", tt.hostname)
+			h, err := HeartbeatForHost("fakeservice", StatusUp)
+			if err != nil {
+				t.Fatalf("HeartbeatForHost() unexpected error = %v", err)
+			}
+			if h.HeartbeatName != tt.want || h.Service != "fakeservice" || h.Status != StatusUp {
+				t.Errorf("HeartbeatForHost() = %+v, want name %q", h, tt.want)
+			}
+		})
+	}
+
+	t.Run("testing custom suffix convention", func(t *testing.T) {
+		t.Setenv("POD_NAME", "")
+		t.Setenv("HOSTNAME", "node-1")
+		format := func(service, host string) string { return host + "." + service + ".hb" }
+		h, err := HeartbeatForHost("fakeservice", StatusUp, WithHostNameFormat(format))
+		if err != nil || h.HeartbeatName != "node-1-fakeservice-hb" {
+			t.Errorf("HeartbeatForHost() = %q, %v, want node-1-fakeservice-hb", h.HeartbeatName, err)
+		}
+	})
+
+	t.Run("testing invalid status", func(t *testing.T) {
+		t.Setenv("HOSTNAME", "node-1")
+		if _, err := HeartbeatForHost("fakeservice", "Up"); err == nil {
+			t.Error("HeartbeatForHost() expected an error for an invalid status")
+		}
+	})
+}
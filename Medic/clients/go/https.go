@@ -0,0 +1,58 @@
+package medic
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ErrPlaintextURL is returned for a Medic URL that isn't https when
+// WithRequireHTTPS is set
+var ErrPlaintextURL = errors.New("medic URL must use https")
+
+// WithRequireHTTPS refuses to send to a Medic URL that isn't https, so
+// credentials are never sent in the clear by a misconfigured build. The
+// base URL and FallbackURLs are checked at construction, which NewClientStrict
+// reports, and every request URL, including a per-call WithRequestBaseURL, is
+// checked before it is sent. Loopback hosts such as localhost and 127.0.0.1,
+// and Unix sockets, are exempt for local development
+func WithRequireHTTPS() Option {
+	return func(c *Client) {
+		c.requireHTTPS = true
+	}
+}
+
+// checkHTTPS validates the client's configured URLs under WithRequireHTTPS
+func (c *Client) checkHTTPS() error {
+	for _, raw := range append([]string{c.BaseURL}, c.FallbackURLs...) {
+		u, err := url.Parse(raw)
+		if err != nil {
+			// Malformed URLs are reported by validateBaseURL
+			continue
+		}
+		if err := requireHTTPS(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requireHTTPS returns an error wrapping ErrPlaintextURL unless u is https
+// or points at a loopback host
+func requireHTTPS(u *url.URL) error {
+	if u.Scheme == "https" || isLoopback(u.Hostname()) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrPlaintextURL, u.Redacted())
+}
+
+// isLoopback reports whether host names this machine
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,46 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithRequireHTTPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		baseURL   string
+		fallbacks []string
+		wantErr   bool
+	}{
+		{name: "testing https", baseURL: "https://medic.example.com"},
+		{name: "testing plain http", baseURL: "http://medic.example.com", wantErr: true},
+		{name: "testing localhost exempt", baseURL: "http://localhost:8080"},
+		{name: "testing ipv4 loopback exempt", baseURL: "http://127.0.0.1:8080"},
+		{name: "testing ipv6 loopback exempt", baseURL: "http://[::1]:8080"},
+		{name: "testing plain http fallback", baseURL: "https://medic-a.example.com", fallbacks: []string{"http://medic-b.example.com"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClientStrict(tt.baseURL, WithFallbackURLs(tt.fallbacks...), WithRequireHTTPS())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClientStrict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrPlaintextURL) {
+				t.Errorf("NewClientStrict() error = %v, want ErrPlaintextURL", err)
+			}
+		})
+	}
+
+	t.Run("testing per-call base URL", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL("https://medic.example.com"), WithRequireHTTPS(), WithDryRun())
+		h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+		if err := c.SendHeartbeatContext(context.Background(), h); err != nil {
+			t.Fatalf("SendHeartbeatContext() unexpected error = %v", err)
+		}
+		err := c.SendHeartbeatContext(context.Background(), h, WithRequestBaseURL("http://medic.eu.example.com"))
+		if !errors.Is(err, ErrPlaintextURL) {
+			t.Errorf("SendHeartbeatContext() error = %v, want ErrPlaintextURL", err)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package medic
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+)
+
+// errInsecureBlocked is the configuration error from WithInsecureSkipVerify
+// in builds with the medic_noinsecure tag
+var errInsecureBlocked = errors.New("WithInsecureSkipVerify is disabled in builds with the medic_noinsecure tag")
+
+// WithInsecureSkipVerify disables TLS certificate verification, for local
+// development against a Medic server with a self-signed certificate. It is
+// never on by default, and every client built with it logs a warning. Build
+// production binaries with -tags medic_noinsecure to make it a configuration
+// error instead
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		if !insecureAllowed {
+			c.setConfigErr(errInsecureBlocked)
+			return
+		}
+		c.insecureSkipVerify = true
+		c.configureTransport(func(t *http.Transport) {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{}
+			} else {
+				t.TLSClientConfig = t.TLSClientConfig.Clone()
+			}
+			t.TLSClientConfig.InsecureSkipVerify = true
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build medic_noinsecure
+
+package medic
+
+// insecureAllowed reports whether WithInsecureSkipVerify may take effect
+const insecureAllowed = false
@@ -0,0 +1,249 @@
+// Wire schema of request bodies sent with WithEncoding(EncodingProtobuf).
+// The Go types in internal/medicpb are generated from it with go generate.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: heartbeat.proto
+
+package medicpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Heartbeat is the body of POST /heartbeat, and each record of
+// /heartbeat/stream, prefixed with its varint length
+type Heartbeat struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	HeartbeatName   string                 `protobuf:"bytes,1,opt,name=heartbeat_name,json=heartbeatName,proto3" json:"heartbeat_name,omitempty"`
+	ServiceName     string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Metadata        map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	IntervalSeconds float64                `protobuf:"fixed64,5,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Severity        string                 `protobuf:"bytes,7,opt,name=severity,proto3" json:"severity,omitempty"`
+	ServiceNames    []string               `protobuf:"bytes,8,rep,name=service_names,json=serviceNames,proto3" json:"service_names,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_heartbeat_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeat_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_heartbeat_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Heartbeat) GetHeartbeatName() string {
+	if x != nil {
+		return x.HeartbeatName
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetIntervalSeconds() float64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetServiceNames() []string {
+	if x != nil {
+		return x.ServiceNames
+	}
+	return nil
+}
+
+// HeartbeatBatch is the body of POST /heartbeat/batch
+type HeartbeatBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Heartbeats    []*Heartbeat           `protobuf:"bytes,1,rep,name=heartbeats,proto3" json:"heartbeats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatBatch) Reset() {
+	*x = HeartbeatBatch{}
+	mi := &file_heartbeat_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatBatch) ProtoMessage() {}
+
+func (x *HeartbeatBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeat_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatBatch.ProtoReflect.Descriptor instead.
+func (*HeartbeatBatch) Descriptor() ([]byte, []int) {
+	return file_heartbeat_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HeartbeatBatch) GetHeartbeats() []*Heartbeat {
+	if x != nil {
+		return x.Heartbeats
+	}
+	return nil
+}
+
+var File_heartbeat_proto protoreflect.FileDescriptor
+
+const file_heartbeat_proto_rawDesc = "" +
+	"\n" +
+	"\x0fheartbeat.proto\x12\bmedic.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8f\x03\n" +
+	"\tHeartbeat\x12%\n" +
+	"\x0eheartbeat_name\x18\x01 \x01(\tR\rheartbeatName\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12=\n" +
+	"\bmetadata\x18\x04 \x03(\v2!.medic.v1.Heartbeat.MetadataEntryR\bmetadata\x12)\n" +
+	"\x10interval_seconds\x18\x05 \x01(\x01R\x0fintervalSeconds\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1a\n" +
+	"\bseverity\x18\a \x01(\tR\bseverity\x12#\n" +
+	"\rservice_names\x18\b \x03(\tR\fserviceNames\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"E\n" +
+	"\x0eHeartbeatBatch\x123\n" +
+	"\n" +
+	"heartbeats\x18\x01 \x03(\v2\x13.medic.v1.HeartbeatR\n" +
+	"heartbeatsB>Z<github.com/linq-team/medic/Medic/clients/go/internal/medicpbb\x06proto3"
+
+var (
+	file_heartbeat_proto_rawDescOnce sync.Once
+	file_heartbeat_proto_rawDescData []byte
+)
+
+func file_heartbeat_proto_rawDescGZIP() []byte {
+	file_heartbeat_proto_rawDescOnce.Do(func() {
+		file_heartbeat_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_heartbeat_proto_rawDesc), len(file_heartbeat_proto_rawDesc)))
+	})
+	return file_heartbeat_proto_rawDescData
+}
+
+var file_heartbeat_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_heartbeat_proto_goTypes = []any{
+	(*Heartbeat)(nil),             // 0: medic.v1.Heartbeat
+	(*HeartbeatBatch)(nil),        // 1: medic.v1.HeartbeatBatch
+	nil,                           // 2: medic.v1.Heartbeat.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_heartbeat_proto_depIdxs = []int32{
+	2, // 0: medic.v1.Heartbeat.metadata:type_name -> medic.v1.Heartbeat.MetadataEntry
+	3, // 1: medic.v1.Heartbeat.timestamp:type_name -> google.protobuf.Timestamp
+	0, // 2: medic.v1.HeartbeatBatch.heartbeats:type_name -> medic.v1.Heartbeat
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_heartbeat_proto_init() }
+func file_heartbeat_proto_init() {
+	if File_heartbeat_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_heartbeat_proto_rawDesc), len(file_heartbeat_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_heartbeat_proto_goTypes,
+		DependencyIndexes: file_heartbeat_proto_depIdxs,
+		MessageInfos:      file_heartbeat_proto_msgTypes,
+	}.Build()
+	File_heartbeat_proto = out.File
+	file_heartbeat_proto_goTypes = nil
+	file_heartbeat_proto_depIdxs = nil
+}
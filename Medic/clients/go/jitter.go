@@ -0,0 +1,75 @@
+package medic
+
+import (
+	"math/rand"
+	"time"
+)
+
+// MaxTickJitter is the largest fraction of the interval WithTickJitter
+// accepts, so a wait is never shorter than half the interval
+const MaxTickJitter = 0.5
+
+// WithStartJitter delays the monitor's first heartbeat by a random duration
+// in [0, interval), so a fleet of instances started together spreads its
+// heartbeats across the interval instead of sending in synchronized waves
+func WithStartJitter() MonitorOption {
+	return func(m *Monitor) {
+		m.jitter.start = true
+	}
+}
+
+// WithTickJitter randomizes the wait between heartbeats to interval plus or
+// minus fraction of the interval, keeping instances from drifting back into
+// step. fraction is clamped to [0, MaxTickJitter]
+func WithTickJitter(fraction float64) MonitorOption {
+	return func(m *Monitor) {
+		switch {
+		case fraction < 0:
+			fraction = 0
+		case fraction > MaxTickJitter:
+			fraction = MaxTickJitter
+		}
+		m.jitter.tick = fraction
+	}
+}
+
+// WithJitterSeed makes the monitor's jitter deterministic, for reproducible tests
+func WithJitterSeed(seed int64) MonitorOption {
+	return func(m *Monitor) {
+		m.jitter.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+// monitorJitter holds a monitor's jitter settings. rand is only used by the
+// monitor's run loop, one at a time
+type monitorJitter struct {
+	start bool
+	tick  float64
+	rand  *rand.Rand
+}
+
+// int63n returns a random number in [0, n) from the seeded source, if any
+func (j *monitorJitter) int63n(n int64) int64 {
+	if n <= 0 {
+		return 0
+	}
+	if j.rand != nil {
+		return j.rand.Int63n(n)
+	}
+	return rand.Int63n(n)
+}
+
+// startDelay returns the delay before the first heartbeat
+func (j *monitorJitter) startDelay(interval time.Duration) time.Duration {
+	if !j.start {
+		return 0
+	}
+	return time.Duration(j.int63n(int64(interval)))
+}
+
+// tickWait returns the wait before the next heartbeat, uniformly within
+// interval plus or minus the tick fraction
+func (j *monitorJitter) tickWait(interval time.Duration) time.Duration {
+	spread := int64(float64(interval) * j.tick)
+	return interval - time.Duration(spread) + time.Duration(j.int63n(2*spread+1))
+}
@@ -0,0 +1,74 @@
+package medic
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// DisabledEnv is the environment variable that turns off every heartbeat
+// send in the process when set to a true value such as "1" or "true"
+const DisabledEnv = "MEDIC_DISABLED"
+
+// killSwitch overrides DisabledEnv once SetDisabled is called
+var killSwitch struct {
+	set      atomic.Bool
+	disabled atomic.Bool
+}
+
+// SetDisabled turns every heartbeat send in the process off or back on,
+// overriding MEDIC_DISABLED, for example from an admin endpoint during a
+// maintenance window
+func SetDisabled(disabled bool) {
+	killSwitch.disabled.Store(disabled)
+	killSwitch.set.Store(true)
+}
+
+// Disabled reports whether heartbeat sends are turned off, by SetDisabled or
+// else by MEDIC_DISABLED. While they are, every send method, sync or async,
+// single, batch, raw or streamed, returns success without building a
+// request. Lookups, deletes and Ping are unaffected
+func Disabled() bool {
+	if killSwitch.set.Load() {
+		return killSwitch.disabled.Load()
+	}
+	disabled, _ := strconv.ParseBool(os.Getenv(DisabledEnv))
+	return disabled
+}
+
+// disabled reports whether sends are turned off, logging it the first time
+// the client skips a send
+func (c *Client) disabled() bool {
+	if !Disabled() {
+		return false
+	}
+	c.disabledLog.Do(c.logDisabled)
+	return true
+}
+
+// disabledBody is the envelope returned for every send skipped by the kill switch
+var disabledBody = []byte(`{"success":true,"message":"heartbeats disabled","results":""}`)
+
+// disabledResponse is the response of a send skipped by the kill switch,
+// with StatusCode 0 since no server answered
+func disabledResponse() *response {
+	return &response{Header: http.Header{}, Body: disabledBody}
+}
+
+// discard drains a stream opened while sends are disabled, so senders
+// never block, until ctx is done or in is closed
+func (s *heartbeatStream) discard(ctx context.Context) {
+	defer close(s.errs)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-s.in:
+			if !ok {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDisabled(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	sends := []struct {
+		name string
+		send func(c *Client) error
+	}{
+		{name: "SendHeartbeat", send: func(c *Client) error { return c.SendHeartbeat(h) }},
+		{name: "SendHeartbeatWithResponse", send: func(c *Client) error {
+			_, err := c.SendHeartbeatWithResponse(context.Background(), h)
+			return err
+		}},
+		{name: "SendHeartbeats", send: func(c *Client) error { return c.SendHeartbeats([]Heartbeat{h}) }},
+		{name: "SendHeartbeatAsync", send: func(c *Client) error { return <-c.SendHeartbeatAsync(h) }},
+		{name: "Do", send: func(c *Client) error {
+			resp, err := c.Do(context.Background(), h)
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			return err
+		}},
+		{name: "StreamHeartbeats", send: func(c *Client) error {
+			beats, errs := c.StreamHeartbeats(context.Background())
+			for range streamBuffer + 1 {
+				beats <- h
+			}
+			close(beats)
+			for err := range errs {
+				return err
+			}
+			return nil
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		env      string
+		set      func()
+		wantHits int32
+	}{
+		{name: "testing env var disables sends", env: "true"},
+		{name: "testing false env var sends", env: "false", wantHits: int32(len(sends))},
+		{name: "testing SetDisabled overrides env", env: "false", set: func() { SetDisabled(true) }},
+		{name: "testing SetDisabled re-enables sends", env: "1", set: func() { SetDisabled(false) }, wantHits: int32(len(sends))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DisabledEnv, tt.env)
+			defer killSwitch.set.Store(false)
+			if tt.set != nil {
+				tt.set()
+			}
+			hits.Store(0)
+			var buf bytes.Buffer
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+			for _, s := range sends {
+				if err := s.send(c); err != nil {
+					t.Errorf("%s() unexpected error = %v", s.name, err)
+				}
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("server hits = %d, want %d", got, tt.wantHits)
+			}
+			if logged := strings.Count(buf.String(), "Heartbeats are disabled"); (tt.wantHits == 0) != (logged == 1) {
+				t.Errorf("logged the disabled notice %d times, want it once only when disabled", logged)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package medic
+
+// Default metadata limits, used for any zero field of MetadataLimits
+const (
+	// MaxMetadataEntries is how many metadata entries validation accepts
+	MaxMetadataEntries = 32
+	// MaxMetadataKeyLength is the longest metadata key validation accepts
+	MaxMetadataKeyLength = 64
+	// MaxMetadataValueLength is the longest metadata value, in bytes,
+	// validation accepts
+	MaxMetadataValueLength = 1024
+)
+
+// MetadataLimits bounds heartbeat Metadata, so a runaway caller is stopped
+// locally instead of spending bandwidth on a body the server rejects. Zero
+// fields use MaxMetadataEntries, MaxMetadataKeyLength and
+// MaxMetadataValueLength
+type MetadataLimits struct {
+	// MaxEntries is the most entries Metadata may hold
+	MaxEntries int
+	// MaxKeyLength is the longest key, in bytes
+	MaxKeyLength int
+	// MaxValueLength is the longest value, in bytes
+	MaxValueLength int
+}
+
+// WithMetadataLimits replaces the default metadata limits for heartbeats
+// sent by the client, for servers that accept more or less metadata.
+// ValidateHeartbeat always applies the defaults
+func WithMetadataLimits(limits MetadataLimits) Option {
+	return func(c *Client) {
+		c.MetadataLimits = limits
+	}
+}
+
+// withDefaults fills zero and negative fields with the default limits
+func (l MetadataLimits) withDefaults() MetadataLimits {
+	if l.MaxEntries <= 0 {
+		l.MaxEntries = MaxMetadataEntries
+	}
+	if l.MaxKeyLength <= 0 {
+		l.MaxKeyLength = MaxMetadataKeyLength
+	}
+	if l.MaxValueLength <= 0 {
+		l.MaxValueLength = MaxMetadataValueLength
+	}
+	return l
+}
@@ -0,0 +1,46 @@
+package medic
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// metadataEntries returns metadata with n distinct entries
+func metadataEntries(n int) map[string]string {
+	m := make(map[string]string, n)
+	for i := range n {
+		m[fmt.Sprintf("key-%d", i)] = "v"
+	}
+	return m
+}
+
+func TestWithMetadataLimits(t *testing.T) {
+	c := NewClientWithOptions(WithDryRun(), WithSilentLogging(), WithMetadataLimits(MetadataLimits{MaxEntries: 2, MaxValueLength: 4}))
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		wantErr  string
+	}{
+		{name: "testing within limits", metadata: map[string]string{"a": "1234", "b": ""}},
+		{name: "testing too many entries", metadata: metadataEntries(3), wantErr: "metadata has 3 entries, more than 2"},
+		{name: "testing long value", metadata: map[string]string{"region": "us-east-1"}, wantErr: `metadata value of key "region" is 9 bytes, longer than 4`},
+		{name: "testing unset field keeps default", metadata: map[string]string{strings.Repeat("k", 65): "v"}, wantErr: "longer than 64 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Metadata: tt.metadata})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("SendHeartbeat() unexpected error = %v", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SendHeartbeat() error = %v, want a *ValidationError containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
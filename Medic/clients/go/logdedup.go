@@ -0,0 +1,108 @@
+package medic
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Log deduplication bounds. A summary is logged at most every window, and
+// the window doubles while a failure keeps recurring, up to
+// maxLogDedupBackoff times the configured window
+const (
+	maxLogDedupEntries = 256
+	maxLogDedupBackoff = 16
+)
+
+// WithLogDeduplication collapses repeated identical failure logs. The first
+// occurrence of a failure is logged right away; repeats within window are
+// counted and later reported as one line such as "42 identical failures in
+// the last 30s". While the failure keeps recurring, the window doubles up to
+// 16 times its starting size, so a long outage logs less and less often.
+// Returned errors are unaffected
+func WithLogDeduplication(window time.Duration) Option {
+	return func(c *Client) {
+		if window <= 0 {
+			c.setConfigErr(fmt.Errorf("log deduplication window must be positive, got %v", window))
+			return
+		}
+		c.logDedup = &logDeduper{window: window, entries: map[string]*dedupEntry{}}
+	}
+}
+
+// logDeduper tracks recent failure log lines by key
+type logDeduper struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+// dedupEntry is the state of one distinct failure
+type dedupEntry struct {
+	// logged is when the failure was last written to the log
+	logged time.Time
+	window time.Duration
+	// repeats counts occurrences suppressed since logged
+	repeats int
+}
+
+// allow reports whether the failure identified by key should be logged at
+// now. When it should, repeats is the number of identical failures
+// suppressed over the preceding since, which the log line summarizes
+func (d *logDeduper) allow(key string, now time.Time) (ok bool, repeats int, since time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, seen := d.entries[key]
+	if !seen {
+		d.evict(now)
+		d.entries[key] = &dedupEntry{logged: now, window: d.window}
+		return true, 0, 0
+	}
+	elapsed := now.Sub(e.logged)
+	if elapsed < e.window {
+		e.repeats++
+		return false, 0, 0
+	}
+	repeats, since = e.repeats, elapsed
+	if repeats > 0 {
+		e.window = min(e.window*2, d.window*maxLogDedupBackoff)
+	} else {
+		// The failure went quiet, so start over at the shortest window
+		e.window = d.window
+	}
+	e.logged, e.repeats = now, 0
+	return true, repeats, since
+}
+
+// evict makes room for a new entry, dropping expired entries first and then
+// an arbitrary one if the deduper is still full
+func (d *logDeduper) evict(now time.Time) {
+	if len(d.entries) < maxLogDedupEntries {
+		return
+	}
+	for key, e := range d.entries {
+		if now.Sub(e.logged) >= e.window {
+			delete(d.entries, key)
+		}
+	}
+	for key := range d.entries {
+		if len(d.entries) < maxLogDedupEntries {
+			return
+		}
+		delete(d.entries, key)
+	}
+}
+
+// dedupFailure applies any log deduplication to a failure log line, returning
+// whether to write it and a summary of the repeats it stands for, if any
+func (c *Client) dedupFailure(key string) (bool, string) {
+	if c.logDedup == nil {
+		return true, ""
+	}
+	ok, repeats, since := c.logDedup.allow(key, c.clock().Now())
+	if !ok || repeats == 0 {
+		return ok, ""
+	}
+	return true, fmt.Sprintf("%d identical failures in the last %v", repeats+1, since.Round(time.Second))
+}
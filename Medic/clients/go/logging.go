@@ -0,0 +1,125 @@
+package medic
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"time"
+)
+
+// WithLogger routes the client's log output through l with structured
+// heartbeat_name, status_code, and error fields
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.Logger = l
+	}
+}
+
+// WithSilentLogging discards all of the client's log output, so failures
+// during an outage don't flood stderr. Errors are still returned to callers
+func WithSilentLogging() Option {
+	return WithLogger(slog.New(slog.DiscardHandler))
+}
+
+// logTransportError logs a request that failed without a response
+func (c *Client) logTransportError(method, label string, err error) {
+	ok, repeats := c.dedupFailure(fmt.Sprintf("%s %s %v", method, label, err))
+	if !ok {
+		return
+	}
+	if c.Logger == nil {
+		log.Printf("%sFailed to %s heartbeat in Medic: %v, Heartbeat: %s", repeatPrefix(repeats), verb(method), err, label)
+		return
+	}
+	attrs := []any{slog.String("heartbeat_name", label), slog.Any("error", err)}
+	if repeats != "" {
+		attrs = append(attrs, slog.String("repeats", repeats))
+	}
+	c.Logger.Error("Failed to "+verb(method)+" heartbeat in Medic", attrs...)
+}
+
+// logDropped logs a heartbeat whose send failed and was ignored by a fail-open caller
+func (c *Client) logDropped(label string, err error) {
+	ok, repeats := c.dedupFailure(fmt.Sprintf("dropped %s %v", label, err))
+	if !ok {
+		return
+	}
+	if c.Logger == nil {
+		log.Printf("%sIgnoring failed heartbeat: %v, Heartbeat: %s", repeatPrefix(repeats), err, label)
+		return
+	}
+	attrs := []any{slog.String("heartbeat_name", label), slog.Any("error", err)}
+	if repeats != "" {
+		attrs = append(attrs, slog.String("repeats", repeats))
+	}
+	c.Logger.Error("Ignoring failed heartbeat", attrs...)
+}
+
+// logDisabled notes that sends are skipped because heartbeats are disabled
+func (c *Client) logDisabled() {
+	if c.Logger == nil {
+		log.Printf("Heartbeats are disabled by %s or SetDisabled; not sending to Medic", DisabledEnv)
+		return
+	}
+	c.Logger.Warn("Heartbeats are disabled; not sending to Medic", slog.String("switch", DisabledEnv))
+}
+
+// logFlapping warns about a heartbeat whose status changed within the debounce window
+func (c *Client) logFlapping(label string, err error) {
+	if c.Logger == nil {
+		log.Printf("Heartbeat status is flapping: %v, Heartbeat: %s", err, label)
+		return
+	}
+	c.Logger.Warn("Heartbeat status is flapping",
+		slog.String("heartbeat_name", label),
+		slog.Any("error", err),
+	)
+}
+
+// logClockSkew warns that the local clock disagrees with the server's
+func (c *Client) logClockSkew(label string, skew time.Duration) {
+	if c.Logger == nil {
+		log.Printf("Local clock differs from Medic server by %v, Heartbeat: %s", skew, label)
+		return
+	}
+	c.Logger.Warn("Local clock differs from Medic server",
+		slog.String("heartbeat_name", label),
+		slog.Duration("skew", skew),
+	)
+}
+
+// logInsecure warns that TLS certificate verification is disabled
+func (c *Client) logInsecure() {
+	if c.Logger == nil {
+		log.Printf("WARNING: TLS certificate verification is disabled for Medic at %s; WithInsecureSkipVerify must not be used in production", c.BaseURL)
+		return
+	}
+	c.Logger.Warn("TLS certificate verification is disabled; WithInsecureSkipVerify must not be used in production",
+		slog.String("base_url", c.BaseURL),
+	)
+}
+
+// logStatusError logs a request that received an unsuccessful status code
+func (c *Client) logStatusError(method, label string, statusCode int) {
+	ok, repeats := c.dedupFailure(fmt.Sprintf("%s %s %d", method, label, statusCode))
+	if !ok {
+		return
+	}
+	if c.Logger == nil {
+		log.Printf("%sFailed to %s heartbeat in Medic: Status_Code: %d, Heartbeat: %s", repeatPrefix(repeats), verb(method), statusCode, label)
+		return
+	}
+	attrs := []any{slog.String("heartbeat_name", label), slog.Int("status_code", statusCode)}
+	if repeats != "" {
+		attrs = append(attrs, slog.String("repeats", repeats))
+	}
+	c.Logger.Warn("Failed to "+verb(method)+" heartbeat in Medic", attrs...)
+}
+
+// repeatPrefix renders a deduplication summary as a log line prefix
+func repeatPrefix(repeats string) string {
+	if repeats == "" {
+		return ""
+	}
+	return repeats + ": "
+}
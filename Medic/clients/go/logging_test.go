@@ -0,0 +1,113 @@
+package medic
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err == nil {
+		t.Fatal("SendHeartbeat() expected an error")
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log output %q is not a single JSON entry: %v", buf.String(), err)
+	}
+	if entry["heartbeat_name"] != "staging-fake-heartbeat-hb" || entry["status_code"] != float64(400) {
+		t.Errorf("log entry = %v, want heartbeat_name and status_code fields", entry)
+	}
+}
+
+func TestWithSilentLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithSilentLogging(), WithDryRun())
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	c.DryRun = false
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err == nil {
+		t.Fatal("SendHeartbeat() expected an error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("silent client logged %q", buf.String())
+	}
+}
+
+func TestWithLogDeduplication(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	clock := newFakeClock()
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithClock(clock),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithLogDeduplication(10*time.Second),
+	)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	send := func(n int) {
+		for i := 0; i < n; i++ {
+			c.SendHeartbeat(h)
+		}
+	}
+	entries := func() []map[string]any {
+		var out []map[string]any
+		dec := json.NewDecoder(&buf)
+		for dec.More() {
+			var entry map[string]any
+			if err := dec.Decode(&entry); err != nil {
+				t.Fatalf("log output is not JSON: %v", err)
+			}
+			out = append(out, entry)
+		}
+		return out
+	}
+
+	send(5)
+	if got := entries(); len(got) != 1 || got[0]["repeats"] != nil {
+		t.Fatalf("first burst logged %v, want one entry without repeats", got)
+	}
+
+	clock.Advance(10 * time.Second)
+	send(1)
+	got := entries()
+	if len(got) != 1 || got[0]["repeats"] != "5 identical failures in the last 10s" {
+		t.Fatalf("after the window logged %v, want one summary of 5 failures", got)
+	}
+
+	// The window doubled, so a repeat after another 10s is still suppressed
+	clock.Advance(10 * time.Second)
+	send(1)
+	clock.Advance(10 * time.Second)
+	send(1)
+	got = entries()
+	if len(got) != 1 || got[0]["repeats"] != "2 identical failures in the last 20s" {
+		t.Errorf("after the doubled window logged %v, want one summary of 2 failures", got)
+	}
+}
@@ -0,0 +1,210 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrHeartbeatNotFound is returned when Medic has no heartbeat registered under the requested name
+var ErrHeartbeatNotFound = errors.New("heartbeat not found")
+
+// heartbeatRecord is a heartbeat as returned by the Medic API
+type heartbeatRecord struct {
+	HeartbeatName string            `json:"heartbeat_name"`
+	Service       string            `json:"service_name"`
+	Services      []string          `json:"service_names"`
+	Status        Status            `json:"status"`
+	Metadata      map[string]string `json:"metadata"`
+	LastSeen      json.RawMessage   `json:"last_seen"`
+	Time          json.RawMessage   `json:"time"`
+}
+
+// toHeartbeat converts a record, taking LastSeen from last_seen or the event time
+func (r heartbeatRecord) toHeartbeat() Heartbeat {
+	h := Heartbeat{HeartbeatName: r.HeartbeatName, Service: r.Service, Services: r.Services, Status: r.Status, Metadata: r.Metadata}
+	if t, ok := parseServerTime(r.LastSeen); ok {
+		h.LastSeen = t
+	} else if t, ok := parseServerTime(r.Time); ok {
+		h.LastSeen = t
+	}
+	return h
+}
+
+// serverTimeLayouts are the timestamp formats Medic is known to return
+var serverTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999-07:00",
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02T15:04:05.999999",
+}
+
+// parseServerTime parses a JSON timestamp string in any known server layout
+func parseServerTime(raw json.RawMessage) (time.Time, bool) {
+	var s string
+	if len(raw) == 0 || json.Unmarshal(raw, &s) != nil || s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range serverTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// GetHeartbeat returns the current state of the named heartbeat using the default client
+func GetHeartbeat(ctx context.Context, name string, opts ...RequestOption) (*Heartbeat, error) {
+	return defaultClient().GetHeartbeat(ctx, name, opts...)
+}
+
+// GetHeartbeat returns Medic's current view of the named heartbeat.
+// It returns ErrHeartbeatNotFound if the heartbeat was never registered
+func (c *Client) GetHeartbeat(ctx context.Context, name string, opts ...RequestOption) (*Heartbeat, error) {
+	if name == "" {
+		return nil, &ValidationError{Field: "heartbeat_name", Message: "is required"}
+	}
+
+	rc := newRequestConfig(opts)
+	endpoint := fmt.Sprintf("%s/heartbeat/%s", c.baseURL(rc), url.PathEscape(name))
+	resp, err := c.doWithRetry(ctx, http.MethodGet, endpoint, nil, name, rc)
+	var se *StatusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrHeartbeatNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, records, err := decodeRecords(resp.Body, c.WireProfile)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrHeartbeatNotFound, name)
+	}
+	h := records[0].toHeartbeat()
+	return &h, nil
+}
+
+// ListHeartbeats returns every heartbeat registered under service using the default client
+func ListHeartbeats(ctx context.Context, service string, opts ...RequestOption) ([]Heartbeat, error) {
+	return defaultClient().ListHeartbeats(ctx, service, opts...)
+}
+
+// ListHeartbeats returns every heartbeat registered under service, following
+// pagination until the last page. It returns an empty slice, never nil, when
+// there are no heartbeats
+func (c *Client) ListHeartbeats(ctx context.Context, service string, opts ...RequestOption) ([]Heartbeat, error) {
+	all := []Heartbeat{}
+	seen := map[string]bool{}
+	cursor := ""
+	for {
+		page, next, err := c.ListHeartbeatsPage(ctx, service, cursor, opts...)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if next == "" {
+			return all, nil
+		}
+		if seen[next] {
+			return nil, fmt.Errorf("heartbeat list pagination repeated cursor %q", next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+// ListHeartbeatsPage returns one page of heartbeats registered under service,
+// starting at cursor ("" for the first page), along with the cursor for the
+// next page, which is empty on the last page
+func (c *Client) ListHeartbeatsPage(ctx context.Context, service, cursor string, opts ...RequestOption) ([]Heartbeat, string, error) {
+	if service == "" {
+		return nil, "", &ValidationError{Field: "service_name", Message: "is required"}
+	}
+
+	query := url.Values{"service_name": {service}}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	rc := newRequestConfig(opts)
+	endpoint := fmt.Sprintf("%s/heartbeats?%s", c.baseURL(rc), query.Encode())
+	resp, err := c.doWithRetry(ctx, http.MethodGet, endpoint, nil, service, rc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	envelope, records, err := decodeRecords(resp.Body, c.WireProfile)
+	if err != nil {
+		return nil, "", err
+	}
+	hs := make([]Heartbeat, len(records))
+	for i, r := range records {
+		hs[i] = r.toHeartbeat()
+	}
+	return hs, envelope.NextCursor, nil
+}
+
+// DeleteHeartbeat deregisters the named heartbeat using the default client
+func DeleteHeartbeat(ctx context.Context, name string, opts ...RequestOption) error {
+	return defaultClient().DeleteHeartbeat(ctx, name, opts...)
+}
+
+// DeleteHeartbeat deregisters the named heartbeat. Deleting a heartbeat that
+// does not exist succeeds unless WithStrictDelete is given
+func (c *Client) DeleteHeartbeat(ctx context.Context, name string, opts ...RequestOption) error {
+	if name == "" {
+		return &ValidationError{Field: "heartbeat_name", Message: "is required"}
+	}
+
+	rc := newRequestConfig(opts)
+	endpoint := fmt.Sprintf("%s/heartbeat/%s", c.baseURL(rc), url.PathEscape(name))
+	_, err := c.doWithRetry(ctx, http.MethodDelete, endpoint, nil, name, rc)
+	var se *StatusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		if rc.strictDelete {
+			return fmt.Errorf("%w: %s", ErrHeartbeatNotFound, name)
+		}
+		return nil
+	}
+	return err
+}
+
+// decodeRecords decodes the results of a Medic envelope holding either one heartbeat or a list
+// with field names translated from profile
+func decodeRecords(body []byte, profile WireProfile) (heartbeatEnvelope, []heartbeatRecord, error) {
+	var envelope heartbeatEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return envelope, nil, fmt.Errorf("failed to decode heartbeat response: %w", err)
+	}
+	results := bytes.TrimSpace(envelope.Results)
+	var objs []json.RawMessage
+	switch {
+	case strings.HasPrefix(string(results), "["):
+		if err := json.Unmarshal(results, &objs); err != nil {
+			return envelope, nil, fmt.Errorf("failed to decode heartbeat response: %w", err)
+		}
+	case strings.HasPrefix(string(results), "{"):
+		objs = []json.RawMessage{results}
+	default:
+		return envelope, nil, nil
+	}
+	records := make([]heartbeatRecord, len(objs))
+	for i, obj := range objs {
+		obj, err := profile.fromWire(obj)
+		if err == nil {
+			err = json.Unmarshal(obj, &records[i])
+		}
+		if err != nil {
+			return envelope, nil, fmt.Errorf("failed to decode heartbeat response: %w", err)
+		}
+	}
+	return envelope, records, nil
+}
@@ -0,0 +1,139 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetHeartbeat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		switch r.URL.Path {
+		case "/heartbeat/staging-fake-heartbeat-hb":
+			w.Write([]byte(`{"success":true,"message":"","results":{"heartbeat_name":"staging-fake-heartbeat-hb","service_name":"fakeservice","service_names":["billing"],"status":"UP","last_seen":"2024-01-01T12:00:00Z"}}`))
+		case "/heartbeat/list-hb":
+			w.Write([]byte(`{"success":true,"message":"","results":[{"heartbeat_name":"list-hb","service_name":"fakeservice","status":"DOWN","time":"2024-01-01 12:00:00"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	tests := []struct {
+		name    string
+		hb      string
+		want    Heartbeat
+		wantErr error
+	}{
+		{
+			name: "testing single result",
+			hb:   "staging-fake-heartbeat-hb",
+			want: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Services: []string{"billing"}, Status: StatusUp, LastSeen: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "testing list result",
+			hb:   "list-hb",
+			want: Heartbeat{HeartbeatName: "list-hb", Service: "fakeservice", Status: StatusDown, LastSeen: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "testing not found",
+			hb:      "missing-hb",
+			wantErr: ErrHeartbeatNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetHeartbeat(context.Background(), tt.hb)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetHeartbeat() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetHeartbeat() unexpected error = %v", err)
+			}
+			if got.HeartbeatName != tt.want.HeartbeatName || got.Service != tt.want.Service ||
+				got.Status != tt.want.Status || !got.LastSeen.Equal(tt.want.LastSeen) {
+				t.Errorf("GetHeartbeat() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListHeartbeats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/heartbeats" {
+			t.Errorf("path = %q, want /heartbeats", r.URL.Path)
+		}
+		switch service, cursor := r.URL.Query().Get("service_name"), r.URL.Query().Get("cursor"); {
+		case service == "empty":
+			w.Write([]byte(`{"success":true,"message":"","results":[]}`))
+		case cursor == "":
+			w.Write([]byte(`{"success":true,"message":"","results":[{"heartbeat_name":"a-hb","service_name":"fakeservice","status":"UP"}],"next_cursor":"page2"}`))
+		case cursor == "page2":
+			w.Write([]byte(`{"success":true,"message":"","results":[{"heartbeat_name":"b-hb","service_name":"fakeservice","status":"UP"}]}`))
+		default:
+			t.Errorf("unexpected cursor %q", cursor)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	hs, err := c.ListHeartbeats(context.Background(), "fakeservice")
+	if err != nil {
+		t.Fatalf("ListHeartbeats() unexpected error = %v", err)
+	}
+	if len(hs) != 2 || hs[0].HeartbeatName != "a-hb" || hs[1].HeartbeatName != "b-hb" {
+		t.Errorf("ListHeartbeats() = %+v, want a-hb and b-hb", hs)
+	}
+
+	hs, err = c.ListHeartbeats(context.Background(), "empty")
+	if err != nil {
+		t.Fatalf("ListHeartbeats() unexpected error = %v", err)
+	}
+	if hs == nil || len(hs) != 0 {
+		t.Errorf("ListHeartbeats() = %#v, want empty non-nil slice", hs)
+	}
+}
+
+func TestDeleteHeartbeat(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		if r.URL.Path == "/heartbeat/staging-fake-heartbeat-hb" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	tests := []struct {
+		name    string
+		hb      string
+		opts    []RequestOption
+		wantErr error
+	}{
+		{name: "testing delete", hb: "staging-fake-heartbeat-hb"},
+		{name: "testing idempotent delete", hb: "missing-hb"},
+		{name: "testing strict delete", hb: "missing-hb", opts: []RequestOption{WithStrictDelete()}, wantErr: ErrHeartbeatNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.DeleteHeartbeat(context.Background(), tt.hb, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteHeartbeat() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package medic
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// WithMarshaler replaces the JSON encoding of single-heartbeat request bodies,
+// for servers that expect a different payload shape, such as the heartbeat
+// wrapped in an envelope. Batch requests keep the default encoding
+func WithMarshaler(fn func(Heartbeat) ([]byte, error)) Option {
+	return func(c *Client) {
+		c.Marshaler = fn
+	}
+}
+
+// marshal builds the request body for h
+func (c *Client) marshal(h Heartbeat) ([]byte, error) {
+	if c.Marshaler != nil {
+		return c.Marshaler(h)
+	}
+	if c.Encoding == EncodingProtobuf {
+		return protoMarshal.Marshal(heartbeatProto(h))
+	}
+	return c.marshalJSON(h)
+}
+
+// marshalJSON encodes h as JSON with the client's wire profile
+func (c *Client) marshalJSON(h Heartbeat) ([]byte, error) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(h); err != nil {
+		return nil, err
+	}
+	return c.WireProfile.toWire(body.Bytes())
+}
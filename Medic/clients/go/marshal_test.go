@@ -0,0 +1,39 @@
+package medic
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithMarshaler(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	envelope := func(h Heartbeat) ([]byte, error) {
+		return json.Marshal(map[string]any{"event": h, "source": "medic-go"})
+	}
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithMarshaler(envelope))
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if want := `{"event":{"heartbeat_name":"staging-fake-heartbeat-hb","status":"UP"},"source":"medic-go"}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+
+	failing := NewClientWithOptions(WithBaseURL(srv.URL), WithMarshaler(func(Heartbeat) ([]byte, error) {
+		return nil, errors.New("boom")
+	}))
+	if err := failing.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb"}); err == nil || !strings.Contains(err.Error(), "failed to encode heartbeat: boom") {
+		t.Errorf("SendHeartbeat() error = %v, want the marshaler error", err)
+	}
+}
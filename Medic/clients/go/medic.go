@@ -2,49 +2,310 @@ package medic
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
 // DefaultBaseURL is the default Medic API base URL
 const DefaultBaseURL = "https://medic.example.com"
 
-var (
-	httpClient = &http.Client{
-		Timeout: 30 * time.Second,
+// maxResponseBytes bounds how much of a response body the client will read
+const maxResponseBytes = 1 << 20
+
+// DefaultTimeout is the request timeout for clients that don't set their
+// own, unless MEDIC_TIMEOUT overrides it
+const DefaultTimeout = 30 * time.Second
+
+// newHTTPClient returns a fresh HTTP client so no two Clients share mutable state
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   GetTimeout(),
+		Transport: newTransport(),
 	}
-)
+}
+
+// zeroValueHTTPClient serves Clients built as a bare struct literal, which
+// have no HTTPClient of their own. It is shared, so it must never be modified:
+// anything that configures the HTTP client copies httpClient() first
+var zeroValueHTTPClient = newHTTPClient()
+
+// httpClient returns the client's HTTPClient, or a default one when it is nil
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return zeroValueHTTPClient
+	}
+	return c.HTTPClient
+}
 
 // Heartbeat represents the heartbeat configuration
 type Heartbeat struct {
-	HeartbeatName string `validate:"required" json:"heartbeat_name"`
-	Service       string `json:"service_name"`
-	Status        string `json:"status"`
+	HeartbeatName string `validate:"required,name" json:"heartbeat_name"`
+	Service       string `validate:"name" json:"service_name,omitempty"`
+	Status        Status `validate:"enum" json:"status,omitempty"`
+	// Severity is how urgently Medic should alert on this heartbeat. It is
+	// omitted when unset, leaving routing to the server's default
+	Severity Severity `validate:"enum" json:"severity,omitempty"`
+	// Services lists further services the heartbeat reports for, so one
+	// beat from a composite service satisfies each of them. Service is kept
+	// for single-service heartbeats; when both are set, Medic groups the
+	// heartbeat under all of them
+	Services []string `validate:"names" json:"service_names,omitempty"`
+	// Metadata is free-form key/value context, such as region or version,
+	// that Medic can group and filter heartbeats by
+	Metadata map[string]string `validate:"keys" json:"metadata,omitempty"`
+	// Interval is how often the heartbeat is sent, so Medic can decide when
+	// it is stale. It is sent in seconds and omitted when zero
+	Interval time.Duration `validate:"positive" json:"interval_seconds,omitempty"`
+	// Timestamp is when the heartbeat event occurred. When set it is sent in
+	// RFC 3339 format as the authoritative event time; when zero it is
+	// omitted and the server uses its receipt time
+	Timestamp time.Time `json:"timestamp,omitempty"`
+	// LastSeen is when Medic last received this heartbeat. It is populated
+	// by GetHeartbeat and never sent
+	LastSeen time.Time `json:"-"`
+	// Extra holds JSON fields this package does not model, so a decoded
+	// heartbeat keeps them when encoded again. Numbers are json.Number
+	Extra map[string]any `json:"-"`
 }
 
-// Client represents a Medic API client
+// String returns a compact form for logs, such as "my-hb/my-service=UP",
+// with Service and Services joined by commas; the services and status are
+// left out when unset
+func (h Heartbeat) String() string {
+	s := h.HeartbeatName
+	services := h.Services
+	if h.Service != "" {
+		services = append([]string{h.Service}, services...)
+	}
+	if len(services) > 0 {
+		s += "/" + strings.Join(services, ",")
+	}
+	if h.Status != "" {
+		s += "=" + string(h.Status)
+	}
+	return s
+}
+
+// heartbeatKeys are the JSON keys of the modeled Heartbeat fields, which
+// Extra cannot override
+var heartbeatKeys = func() map[string]bool {
+	keys := map[string]bool{}
+	rt := reflect.TypeOf(Heartbeat{})
+	for i := 0; i < rt.NumField(); i++ {
+		if tag := rt.Field(i).Tag.Get("json"); tag != "" && tag != "-" {
+			keys[fieldName(rt.Field(i))] = true
+		}
+	}
+	return keys
+}()
+
+// MarshalJSON encodes the heartbeat, sending Interval in seconds and omitting
+// Timestamp when it is zero, which the omitempty tag alone does not do for
+// time.Time. Extra fields follow the modeled ones in sorted key order
+func (h Heartbeat) MarshalJSON() ([]byte, error) {
+	type heartbeat Heartbeat
+	wire := struct {
+		heartbeat
+		Interval  float64 `json:"interval_seconds,omitempty"`
+		Timestamp string  `json:"timestamp,omitempty"`
+	}{heartbeat: heartbeat(h), Interval: h.Interval.Seconds()}
+	if !h.Timestamp.IsZero() {
+		wire.Timestamp = h.Timestamp.Format(time.RFC3339)
+	}
+	data, err := json.Marshal(wire)
+	if err != nil || len(h.Extra) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(h.Extra))
+	for k := range h.Extra {
+		if !heartbeatKeys[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, k := range keys {
+		v, err := json.Marshal(h.Extra[k])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode extra field %q: %w", k, err)
+		}
+		name, _ := json.Marshal(k)
+		buf.WriteByte(',')
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a heartbeat encoded by MarshalJSON, keeping any
+// unknown fields in Extra
+func (h *Heartbeat) UnmarshalJSON(data []byte) error {
+	type heartbeat Heartbeat
+	wire := struct {
+		*heartbeat
+		Interval float64 `json:"interval_seconds"`
+	}{heartbeat: (*heartbeat)(h)}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	h.Interval = time.Duration(wire.Interval * float64(time.Second))
+
+	var fields map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return err
+	}
+	h.Extra = nil
+	for k, v := range fields {
+		if heartbeatKeys[k] {
+			continue
+		}
+		if h.Extra == nil {
+			h.Extra = map[string]any{}
+		}
+		h.Extra[k] = v
+	}
+	return nil
+}
+
+// Client represents a Medic API client. The zero value is ready to use: an
+// empty BaseURL resolves from MEDIC_BASE_URL or DefaultBaseURL, and a nil
+// HTTPClient uses a default client with the MEDIC_TIMEOUT in effect when the
+// package was loaded, or DefaultTimeout.
+//
+// A Client is safe for concurrent use. Constructors give each Client its own
+// HTTP client and transport; zero-value Clients share one that is never
+// modified, since options copy it first. Fields must not be changed once
+// requests are in flight
 type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
+	// Doer sends requests in place of HTTPClient when set, for callers that
+	// wrap outbound HTTP in their own instrumented client
+	Doer Doer
+	// SuccessPredicate reports whether a response status code is a success;
+	// nil accepts every status below 300
+	SuccessPredicate func(statusCode int) bool
+	// Retry controls retries of transient failures; zero fields use DefaultRetryConfig
+	Retry RetryConfig
+	// RetryPolicy decides which failed attempts are retried; nil uses
+	// DefaultRetryPolicy
+	RetryPolicy RetryPolicy
+	// UserAgent overrides the default User-Agent header of DefaultUserAgent
+	UserAgent string
+	// AuthToken is sent as "Authorization: Bearer <token>" when set
+	AuthToken string
+	// Headers are added to every request. Content-Type always follows
+	// Encoding and cannot be overridden here
+	Headers http.Header
+	// ContextHeaderFunc derives extra headers for each request from its
+	// context when set
+	ContextHeaderFunc func(context.Context) http.Header
+	// Tracer instruments requests with distributed tracing when set
+	Tracer Tracer
+	// Metrics observes every request attempt when set
+	Metrics Metrics
+	// BodyValidator checks each JSON heartbeat body before it is sent when set
+	BodyValidator BodyValidator
+	// Logger receives the client's log output; when nil the standard library
+	// logger is used
+	Logger *slog.Logger
+	// MaxInFlight caps concurrent async sends; zero uses DefaultMaxInFlight
+	MaxInFlight int
+	// NamePattern is the pattern heartbeat and service names must match;
+	// nil uses DefaultNamePattern
+	NamePattern *regexp.Regexp
+	// MetadataLimits bounds heartbeat Metadata; zero fields use the defaults
+	MetadataLimits MetadataLimits
+	// Clock tells time for retries and the circuit breaker; nil uses the real clock
+	Clock Clock
+	// Marshaler builds the request body for a single heartbeat; nil encodes
+	// the heartbeat in Encoding
+	Marshaler func(Heartbeat) ([]byte, error)
+	// Encoding is the wire format of request bodies; the zero value is JSON
+	Encoding Encoding
+	// WireProfile renames heartbeat JSON fields for older servers; the zero
+	// value uses the current names
+	WireProfile WireProfile
+	// DryRun validates and builds every request, then logs it instead of
+	// sending it
+	DryRun bool
+	// RateLimiter throttles outgoing requests when set
+	RateLimiter RateLimiter
+	// CompressionThreshold gzips request bodies of at least this many bytes;
+	// zero disables compression
+	CompressionThreshold int
+	// ClockSkewThreshold logs a warning when a heartbeat response's Date
+	// header differs from the local clock by more than this; zero disables it
+	ClockSkewThreshold time.Duration
+	// FallbackURLs are standby Medic servers tried in order, with the same
+	// path, when requests to BaseURL still fail after retries
+	FallbackURLs []string
+	// BasePath is prepended to every endpoint path, for servers mounted under
+	// a prefix such as "/api/v2/medic"
+	BasePath string
+	// CapturedHeaders names response headers, besides X-Request-ID, copied
+	// into HeartbeatResponse and StatusError
+	CapturedHeaders []string
+
+	// apiKey records that WithAPIKey supplied credentials, so the
+	// MEDIC_API_TOKEN fallback is skipped
+	apiKey bool
+
+	// requireHTTPS makes every request to a non-loopback http URL fail
+	requireHTTPS bool
+
+	// insecureSkipVerify records that WithInsecureSkipVerify disabled TLS
+	// verification, so construction warns about it
+	insecureSkipVerify bool
+
+	// middleware wraps the transport once all options are applied
+	middleware []Middleware
+	// recorder records every exchange on the transport when set
+	recorder *recorder
+	// requestEditors run on each request just before it is sent
+	requestEditors []RequestEditor
+	// logDedup collapses repeated failure logs when set
+	logDedup *logDeduper
+
+	// breaker fails requests fast after repeated failures when set
+	breaker *circuitBreaker
+
+	// configErr is an error from applying options, returned by every request
+	configErr error
+
+	asyncOnce sync.Once
+	asyncSem  chan struct{}
+
+	// disabledLog logs the first send skipped because heartbeats are disabled
+	disabledLog sync.Once
+
+	stats     sendStats
+	lifecycle lifecycle
 }
 
 // NewClient creates a new Medic client with the given base URL
 // If baseURL is empty, it will use MEDIC_BASE_URL env var or the default
 func NewClient(baseURL string) *Client {
-	if baseURL == "" {
-		baseURL = os.Getenv("MEDIC_BASE_URL")
-		if baseURL == "" {
-			baseURL = DefaultBaseURL
-		}
-	}
-	return &Client{
-		BaseURL:    baseURL,
-		HTTPClient: httpClient,
-	}
+	return NewClientWithOptions(WithBaseURL(baseURL))
 }
 
 // GetBaseURL returns the Medic API base URL from environment or default
@@ -55,33 +316,321 @@ func GetBaseURL() string {
 	return DefaultBaseURL
 }
 
+// GetTimeout returns the request timeout from the MEDIC_TIMEOUT env var, a
+// Go duration such as "2s", or DefaultTimeout when it is unset, malformed,
+// or not positive
+func GetTimeout() time.Duration {
+	d, err := parseTimeout(os.Getenv("MEDIC_TIMEOUT"))
+	if err != nil {
+		if _, logged := loggedTimeoutErrs.LoadOrStore(err.Error(), true); !logged {
+			log.Printf("Ignoring %v, using the default timeout of %v", err, DefaultTimeout)
+		}
+		return DefaultTimeout
+	}
+	if d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// loggedTimeoutErrs holds the MEDIC_TIMEOUT errors GetTimeout already
+// logged, so each malformed value is logged once
+var loggedTimeoutErrs sync.Map
+
+// parseTimeout parses a MEDIC_TIMEOUT value, returning 0 for an empty one
+func parseTimeout(s string) (time.Duration, error) {
+	if s = strings.TrimSpace(s); s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid MEDIC_TIMEOUT %q: %w", s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid MEDIC_TIMEOUT %q: must be positive", s)
+	}
+	return d, nil
+}
+
+// GetAPIToken returns the Medic API token from environment, if any
+func GetAPIToken() string {
+	return os.Getenv("MEDIC_API_TOKEN")
+}
+
 // SendHeartbeat sends a heartbeat post to medic using the default client
 func SendHeartbeat(h Heartbeat) error {
-	return NewClient("").SendHeartbeat(h)
+	return defaultClient().SendHeartbeat(h)
+}
+
+// SendHeartbeatContext sends a heartbeat post to medic using the default client
+// and the given context
+func SendHeartbeatContext(ctx context.Context, h Heartbeat, opts ...RequestOption) error {
+	return defaultClient().SendHeartbeatContext(ctx, h, opts...)
 }
 
 // SendHeartbeat sends a heartbeat post to medic
 func (c *Client) SendHeartbeat(h Heartbeat) error {
+	return c.SendHeartbeatContext(context.Background(), h)
+}
+
+// SendHeartbeatContext sends a heartbeat post to medic, aborting the request
+// when ctx is cancelled or its deadline passes
+func (c *Client) SendHeartbeatContext(ctx context.Context, h Heartbeat, opts ...RequestOption) error {
+	_, err := c.sendHeartbeat(ctx, h, opts)
+	return err
+}
+
+// sendHeartbeat validates, encodes, and posts h, returning the successful response
+func (c *Client) sendHeartbeat(ctx context.Context, h Heartbeat, opts []RequestOption) (resp *response, err error) {
+	if c.disabled() {
+		return disabledResponse(), nil
+	}
+	ctx, end := c.startSpan(ctx, "medic.SendHeartbeat")
+	rejected := false
+	defer func() {
+		end(resp, err)
+		c.recordOutcome(rejected, statusCode(resp, err), err)
+	}()
+
+	// Validate before making any request
+	if err := c.validate(h); err != nil {
+		rejected = true
+		return nil, fmt.Errorf("invalid heartbeat: %w", err)
+	}
+
 	// Configure the body content
-	var body bytes.Buffer
-	if err := json.NewEncoder(&body).Encode(h); err != nil {
-		return fmt.Errorf("failed to encode heartbeat: %w", err)
+	body, err := c.marshal(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode heartbeat: %w", err)
 	}
 
-	// Make the request to medic
-	url := fmt.Sprintf("%s/heartbeat", c.BaseURL)
-	resp, err := c.HTTPClient.Post(url, "application/json", &body)
+	// Make the request to medic, retrying transient failures
+	rc := newRequestConfig(opts)
+	if err := c.validateBody(ctx, c.baseURL(rc), body); err != nil {
+		rejected = true
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/heartbeat", c.baseURL(rc))
+	resp, err = c.doWithRetry(ctx, http.MethodPost, url, body, h.HeartbeatName, rc)
 	if err != nil {
-		log.Printf("Failed to post heartbeat in Medic: %v, Heartbeat: %s", err, h.HeartbeatName)
-		return fmt.Errorf("heartbeat post failure: %w", err)
+		return nil, err
+	}
+	// A proxy can answer 2xx with an HTML page that Medic never saw
+	if err := checkJSONBody(resp, false); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	c.checkClockSkew(h.HeartbeatName, resp)
+	return resp, nil
+}
+
+// response is a fully read HTTP response
+type response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// doWithRetry sends a request to url, retrying transient failures and then
+// trying any FallbackURLs, and returns the successful response. label
+// identifies the heartbeat in logs
+func (c *Client) doWithRetry(ctx context.Context, method, url string, payload []byte, label string, rc *requestConfig) (*response, error) {
+	if c.configErr != nil {
+		return nil, fmt.Errorf("invalid client configuration: %w", c.configErr)
+	}
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := rc.withTimeout(ctx)
+	defer cancel()
 
-	// Check the status code for success
-	if resp.StatusCode >= 300 {
-		log.Printf("Failed to post heartbeat in Medic: Status_Code: %d, Heartbeat: %s", resp.StatusCode, h.HeartbeatName)
-		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	// One key per logical send, reused by every retry so the server can dedupe
+	if method == http.MethodPost && rc.idempotencyKey == "" {
+		rc.idempotencyKey = newIdempotencyKey()
+	}
+	// Compress once up front; every attempt sends the same bytes
+	if payload != nil {
+		payload, rc.contentEncoding = c.compress(payload)
 	}
 
-	return nil
+	resp, result, err := c.retry(ctx, method, url, payload, label, rc)
+	// An open circuit on the primary is exactly when a standby should be used
+	failover := result.transient || errors.Is(err, ErrCircuitOpen)
+	if err == nil || !failover || ctx.Err() != nil || len(c.FallbackURLs) == 0 || rc.baseURL != "" {
+		return resp, err
+	}
+	// The primary is unreachable or failing; try the same path on each fallback
+	primary := c.baseURL(rc)
+	path, ok := strings.CutPrefix(url, primary)
+	if !ok {
+		return resp, err
+	}
+	errs := []error{fmt.Errorf("%s: %w", primary, err)}
+	defer func() { rc.fallback = "" }()
+	for _, fallback := range c.FallbackURLs {
+		base := fallback + normalizeBasePath(c.BasePath)
+		rc.fallback = base
+		resp, result, err = c.retry(ctx, method, base+path, payload, label, rc)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", base, err))
+		if !(result.transient || errors.Is(err, ErrCircuitOpen)) || ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("all Medic endpoints failed: %w", errors.Join(errs...))
+}
+
+// retry sends a request to url, retrying failures the retry policy allows,
+// and returns the last attempt's outcome
+func (c *Client) retry(ctx context.Context, method, url string, payload []byte, label string, rc *requestConfig) (*response, attemptResult, error) {
+	retry := c.Retry.withDefaults()
+	var resp *response
+	var err error
+	var result attemptResult
+	attempts := 0
+	for attempts < retry.MaxAttempts {
+		if attempts > 0 {
+			// Honor the server's Retry-After when it asks for a longer wait
+			wait := retry.backoff(attempts)
+			if result.retryAfter > wait {
+				wait = result.retryAfter
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				break
+			}
+			if sleepErr := sleepContext(ctx, c.clock(), wait); sleepErr != nil {
+				return nil, attemptResult{}, newTransportError(method, url, sleepErr)
+			}
+		}
+		attempts++
+
+		resp, result, err = c.do(ctx, method, url, payload, label, rc)
+		if err == nil || !result.retryable {
+			break
+		}
+	}
+	if err != nil && attempts > 1 {
+		return nil, result, fmt.Errorf("heartbeat failed after %d attempts: %w", attempts, err)
+	}
+	return resp, result, err
+}
+
+// do makes a single request attempt and reports whether a failure is retryable.
+// A nil payload sends no body
+func (c *Client) do(ctx context.Context, method, url string, payload []byte, label string, rc *requestConfig) (resp *response, result attemptResult, err error) {
+	if c.DryRun {
+		return c.dryRun(ctx, method, url, payload, label, rc)
+	}
+	result, err = c.roundTrip(ctx, method, url, payload, label, rc, func(req *http.Request, httpResp *http.Response) (attemptResult, error) {
+		defer httpResp.Body.Close()
+		respBody, readErr := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBytes))
+
+		// Check the status code for success
+		if !c.isSuccess(httpResp.StatusCode) {
+			c.logStatusError(method, label, httpResp.StatusCode)
+			result := attemptResult{
+				retryable: c.shouldRetry(req, httpResp, nil),
+				transient: retryableStatus(httpResp.StatusCode),
+			}
+			if httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode == http.StatusServiceUnavailable {
+				result.retryAfter, _ = parseRetryAfter(httpResp.Header.Get("Retry-After"), c.clock().Now())
+			}
+			se := newStatusError(httpResp.StatusCode, respBody)
+			se.RequestID = httpResp.Header.Get(RequestIDHeader)
+			se.Headers = c.captureHeaders(httpResp.Header)
+			return result, se
+		}
+
+		if readErr != nil {
+			return attemptResult{}, newTransportError(method, url, fmt.Errorf("failed to read response: %w", readErr))
+		}
+		resp = &response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: respBody}
+		return attemptResult{}, nil
+	})
+	if err != nil {
+		return nil, result, err
+	}
+	return resp, result, nil
+}
+
+// roundTrip makes one request through the circuit breaker and rate limiter,
+// observing it for metrics, and hands any response to handle to classify.
+// Every outcome after the breaker admits the request is recorded, so a
+// request that fails before it is sent can't leave a probe outstanding
+func (c *Client) roundTrip(ctx context.Context, method, url string, payload []byte, label string, rc *requestConfig, handle func(req *http.Request, httpResp *http.Response) (attemptResult, error)) (result attemptResult, err error) {
+	if breaker := c.circuit(rc); breaker != nil {
+		if err := breaker.allow(c.clock().Now()); err != nil {
+			return attemptResult{}, err
+		}
+		defer func() { c.recordCircuit(breaker, result, err) }()
+	}
+	if err := c.waitRateLimit(ctx, method, url); err != nil {
+		return attemptResult{}, err
+	}
+	start := time.Now()
+	code := 0
+	defer func() { c.observe(start, code, err) }()
+
+	req, err := c.newRequest(ctx, method, url, payload, rc)
+	if err != nil {
+		return attemptResult{}, err
+	}
+
+	req, timer := c.traceTiming(req)
+	defer func() { c.observeTiming(timer, code, err) }()
+
+	httpResp, err := c.requestDoer(rc).Do(req)
+	if err != nil {
+		c.logTransportError(method, label, err)
+		// Surface cancellation directly so callers can match on ctx.Err()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return attemptResult{}, newTransportError(method, url, ctxErr)
+		}
+		return attemptResult{retryable: c.shouldRetry(req, nil, err), transient: true}, newTransportError(method, url, err)
+	}
+	code = httpResp.StatusCode
+	return handle(req, httpResp)
+}
+
+// newRequest builds a request with all headers applied. A nil payload sends no body
+func (c *Client) newRequest(ctx context.Context, method, url string, payload []byte, rc *requestConfig) (*http.Request, error) {
+	if payload == nil {
+		return c.newBodyRequest(ctx, method, url, nil, "", rc)
+	}
+	return c.newBodyRequest(ctx, method, url, bytes.NewReader(payload), c.contentType(), rc)
+}
+
+// newBodyRequest builds a request that sends body as contentType, with all
+// headers applied
+func (c *Client) newBodyRequest(ctx context.Context, method, url string, body io.Reader, contentType string, rc *requestConfig) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build heartbeat request: %w", err)
+	}
+	if c.requireHTTPS {
+		if err := requireHTTPS(req.URL); err != nil {
+			return nil, err
+		}
+	}
+	c.applyHeaders(req, rc, contentType)
+	if c.Tracer != nil {
+		c.Tracer.Inject(ctx, req.Header)
+	}
+	if err := c.editRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// verb describes an HTTP method for log messages
+func verb(method string) string {
+	switch method {
+	case http.MethodPost:
+		return "post"
+	case http.MethodDelete:
+		return "delete"
+	default:
+		return "get"
+	}
 }
@@ -1,17 +1,43 @@
 package medic
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 func TestSendHeartbeat(t *testing.T) {
+	// Stub Medic: only the registered heartbeat is accepted, like the real
+	// server, which responds 404 for unknown heartbeat names
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var h Heartbeat
+		if err := json.NewDecoder(r.Body).Decode(&h); err != nil || h.HeartbeatName != "staging-fake-heartbeat-hb" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	t.Setenv("MEDIC_BASE_URL", srv.URL)
+
 	type args struct {
 		h Heartbeat
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name      string
+		args      args
+		wantErr   bool
+		wantCalls int32
 	}{
-		// TODO: Add test cases.
 		{
 			name: "testing invalid name",
 			args: args{
@@ -21,7 +47,8 @@ func TestSendHeartbeat(t *testing.T) {
 					Status:        "UP",
 				},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantCalls: 0,
 		},
 		{
 			name: "testing no name",
@@ -32,7 +59,8 @@ func TestSendHeartbeat(t *testing.T) {
 					Status:        "UP",
 				},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantCalls: 0,
 		},
 		{
 			name: "testing success",
@@ -43,14 +71,219 @@ func TestSendHeartbeat(t *testing.T) {
 					Status:        "UP",
 				},
 			},
-			wantErr: false,
+			wantErr:   false,
+			wantCalls: 1,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
 			if err := SendHeartbeat(tt.args.h); (err != nil) != tt.wantErr {
 				t.Errorf("SendHeartbeat() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("server called %d times, want %d", got, tt.wantCalls)
+			}
 		})
 	}
 }
+
+func TestSendHeartbeatContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Status: "UP"}
+	err := NewClient(srv.URL).SendHeartbeatContext(ctx, h)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SendHeartbeatContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestHeartbeatJSON(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		h    Heartbeat
+		want string
+	}{
+		{
+			name: "testing zero timestamp omitted",
+			h:    Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Status: StatusUp},
+			want: `{"heartbeat_name":"staging-fake-heartbeat-hb","service_name":"fakeservice","status":"UP"}`,
+		},
+		{
+			name: "testing empty service and status omitted",
+			h:    Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb"},
+			want: `{"heartbeat_name":"staging-fake-heartbeat-hb"}`,
+		},
+		{
+			name: "testing multiple services",
+			h:    Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Services: []string{"billing", "invoices"}},
+			want: `{"heartbeat_name":"staging-fake-heartbeat-hb","service_name":"fakeservice","service_names":["billing","invoices"]}`,
+		},
+		{
+			name: "testing interval sent in seconds",
+			h:    Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Interval: 90 * time.Second},
+			want: `{"heartbeat_name":"staging-fake-heartbeat-hb","interval_seconds":90}`,
+		},
+		{
+			name: "testing timestamp sent as RFC 3339",
+			h:    Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Status: StatusUp, Timestamp: at},
+			want: `{"heartbeat_name":"staging-fake-heartbeat-hb","service_name":"fakeservice","status":"UP","timestamp":"2024-05-01T12:30:00Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.h)
+			if err != nil {
+				t.Fatalf("json.Marshal() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+			var back Heartbeat
+			if err := json.Unmarshal(got, &back); err != nil || !back.Timestamp.Equal(tt.h.Timestamp) || back.Interval != tt.h.Interval {
+				t.Errorf("round trip = %+v (err %v), want %+v", back, err, tt.h)
+			}
+		})
+	}
+}
+
+func TestHeartbeatJSONRoundTrip(t *testing.T) {
+	h := Heartbeat{
+		HeartbeatName: "staging-fake-heartbeat-hb",
+		Service:       "fakeservice",
+		Status:        StatusDegraded,
+		Metadata:      map[string]string{"region": "us-east-1"},
+		Interval:      90 * time.Second,
+		Timestamp:     time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC),
+	}
+	first, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error = %v", err)
+	}
+	// A field the server added that this package does not model
+	first = append(first[:len(first)-1], []byte(`,"owner":{"pager":12345678901234567890,"team":"sre"}}`)...)
+
+	var back Heartbeat
+	if err := json.Unmarshal(first, &back); err != nil {
+		t.Fatalf("json.Unmarshal() unexpected error = %v", err)
+	}
+	if back.String() != h.String() || !reflect.DeepEqual(back.Metadata, h.Metadata) ||
+		back.Interval != h.Interval || !back.Timestamp.Equal(h.Timestamp) {
+		t.Errorf("json.Unmarshal() = %+v, want %+v", back, h)
+	}
+	if _, ok := back.Extra["owner"]; !ok || len(back.Extra) != 1 {
+		t.Errorf("Extra = %v, want only owner", back.Extra)
+	}
+	second, err := json.Marshal(back)
+	if err != nil {
+		t.Fatalf("json.Marshal() unexpected error = %v", err)
+	}
+	if string(second) != string(first) {
+		t.Errorf("round trip changed the encoding:\n got %s\nwant %s", second, first)
+	}
+}
+
+func TestHeartbeatString(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Heartbeat
+		want string
+	}{
+		{name: "testing full heartbeat", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Status: StatusUp}, want: "staging-fake-heartbeat-hb/fakeservice=UP"},
+		{name: "testing no service", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusDown}, want: "staging-fake-heartbeat-hb=DOWN"},
+		{name: "testing name only", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb"}, want: "staging-fake-heartbeat-hb"},
+		{name: "testing multiple services", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Services: []string{"billing", "invoices"}, Status: StatusUp}, want: "staging-fake-heartbeat-hb/fakeservice,billing,invoices=UP"},
+		{name: "testing services only", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Services: []string{"billing"}}, want: "staging-fake-heartbeat-hb/billing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZeroValueClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/heartbeat" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	t.Setenv("MEDIC_BASE_URL", srv.URL+"/")
+
+	var c Client
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Errorf("zero-value Client SendHeartbeat() error = %v", err)
+	}
+	if err := (&Client{}).Ping(context.Background()); err == nil {
+		t.Error("Ping() against a stub without /health expected an error")
+	}
+	if c.HTTPClient != nil || c.BaseURL != "" {
+		t.Error("sending modified the zero-value Client")
+	}
+}
+
+// TestConcurrentSends runs sends from several clients at once, while new
+// clients are being configured, so the race detector can catch any state
+// shared between clients
+func TestConcurrentSends(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	t.Setenv("MEDIC_BASE_URL", srv.URL)
+
+	clients := []*Client{
+		NewClient(srv.URL),
+		NewClient(srv.URL),
+		{},
+		{},
+	}
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	const perClient = 20
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		for i := 0; i < perClient; i++ {
+			wg.Add(1)
+			go func(c *Client) {
+				defer wg.Done()
+				if err := c.SendHeartbeatContext(context.Background(), h); err != nil {
+					t.Errorf("SendHeartbeatContext() unexpected error = %v", err)
+				}
+			}(c)
+		}
+	}
+	// Configuring other clients must not touch the ones sending
+	for i := 0; i < perClient; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithTimeout(time.Second), WithMaxIdleConns(1))
+			c.HTTPClient.Timeout = 2 * time.Second
+		}()
+	}
+	wg.Wait()
+
+	if got, want := atomic.LoadInt32(&calls), int32(len(clients)*perClient); got != want {
+		t.Errorf("server received %d heartbeats, want %d", got, want)
+	}
+	if clients[0].HTTPClient == clients[1].HTTPClient || clients[0].HTTPClient.Transport == clients[1].HTTPClient.Transport {
+		t.Error("clients from NewClient share an HTTP client or transport")
+	}
+}
@@ -0,0 +1,87 @@
+// Package medicotel provides an OpenTelemetry tracer for the Medic client.
+//
+// It lives in its own package so the core client has no OpenTelemetry
+// dependency unless tracing is wired in:
+//
+//	client := medic.NewClientWithOptions(medic.WithTracer(medicotel.NewTracer()))
+package medicotel
+
+import (
+	"context"
+	"net/http"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by this package
+const instrumentationName = "github.com/linq-team/medic/Medic/clients/go"
+
+// Option configures a Tracer
+type Option func(*Tracer)
+
+// Tracer implements medic.Tracer using OpenTelemetry
+type Tracer struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	tracer     trace.Tracer
+}
+
+var _ medic.Tracer = (*Tracer)(nil)
+
+// NewTracer creates a Tracer using the global tracer provider and propagator
+// unless overridden by opts. With no provider configured, spans are no-ops
+func NewTracer(opts ...Option) *Tracer {
+	t := &Tracer{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.tracer = t.provider.Tracer(instrumentationName)
+	return t
+}
+
+// WithTracerProvider sets the tracer provider used to create spans
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		if tp != nil {
+			t.provider = tp
+		}
+	}
+}
+
+// WithPropagator sets the propagator used to inject trace context into requests
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(t *Tracer) {
+		if p != nil {
+			t.propagator = p
+		}
+	}
+}
+
+// StartSpan starts a client span and returns a function that records the
+// response status code and any error before ending it
+func (t *Tracer) StartSpan(ctx context.Context, name string) (context.Context, func(statusCode int, err error)) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, func(statusCode int, err error) {
+		if statusCode != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// Inject writes the trace context from ctx into the request headers
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,61 @@
+package medicotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewTracer(WithTracerProvider(tp), WithPropagator(propagation.TraceContext{}))
+	client := medic.NewClientWithOptions(medic.WithBaseURL(srv.URL), medic.WithTracer(tracer))
+
+	err := client.SendHeartbeatContext(context.Background(), medic.Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: medic.StatusUp})
+	if err == nil {
+		t.Fatal("SendHeartbeatContext() expected an error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "medic.SendHeartbeat" {
+		t.Errorf("span name = %q, want medic.SendHeartbeat", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want Error", span.Status().Code)
+	}
+	if !hasAttribute(span.Attributes(), attribute.Int("http.response.status_code", http.StatusBadRequest)) {
+		t.Errorf("span attributes %v missing status code", span.Attributes())
+	}
+	if traceparent == "" {
+		t.Error("traceparent header was not propagated")
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
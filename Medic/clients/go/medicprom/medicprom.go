@@ -0,0 +1,84 @@
+// Package medicprom provides Prometheus metrics for the Medic client.
+//
+// It lives in its own package so the core client has no Prometheus
+// dependency unless metrics are wired in:
+//
+//	metrics := medicprom.NewMetrics(prometheus.DefaultRegisterer)
+//	client := medic.NewClientWithOptions(medic.WithMetrics(metrics))
+package medicprom
+
+import (
+	"strconv"
+	"time"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements medic.TimingMetrics with a send counter, a latency
+// histogram, a histogram of request phases and a connection counter
+type Metrics struct {
+	sends       *prometheus.CounterVec
+	duration    prometheus.Histogram
+	phases      *prometheus.HistogramVec
+	connections *prometheus.CounterVec
+}
+
+var _ medic.TimingMetrics = (*Metrics)(nil)
+
+// NewMetrics creates the client metrics and registers them with reg, if non-nil
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		sends: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "medic_client_sends_total",
+			Help: "Heartbeat request attempts by response status code, or \"error\" when no response was received.",
+		}, []string{"status"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "medic_client_request_duration_seconds",
+			Help:    "Latency of heartbeat request attempts.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		phases: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "medic_client_request_phase_seconds",
+			Help:    "Time heartbeat request attempts spent in each phase: dns, connect, tls, or ttfb for time to first byte.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"phase"}),
+		connections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "medic_client_connections_total",
+			Help: "Connections used by heartbeat request attempts, by whether they were reused from the pool.",
+		}, []string{"reused"}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.sends, m.duration, m.phases, m.connections)
+	}
+	return m
+}
+
+// ObserveSend records one request attempt
+func (m *Metrics) ObserveSend(duration time.Duration, statusCode int, err error) {
+	status := "error"
+	if statusCode != 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	m.sends.WithLabelValues(status).Inc()
+	m.duration.Observe(duration.Seconds())
+}
+
+// ObserveTiming records the phases of one request attempt. Phases that did
+// not happen, such as DNS on a reused connection, are not observed
+func (m *Metrics) ObserveTiming(timing medic.RequestTiming, statusCode int, err error) {
+	for phase, d := range map[string]time.Duration{
+		"dns":     timing.DNS,
+		"connect": timing.Connect,
+		"tls":     timing.TLSHandshake,
+		"ttfb":    timing.TimeToFirstByte,
+	} {
+		if d > 0 {
+			m.phases.WithLabelValues(phase).Observe(d.Seconds())
+		}
+	}
+	// Without a response there may have been no connection to count
+	if statusCode != 0 {
+		m.connections.WithLabelValues(strconv.FormatBool(timing.ConnReused)).Inc()
+	}
+}
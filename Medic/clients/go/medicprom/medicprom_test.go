@@ -0,0 +1,49 @@
+package medicprom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.ObserveSend(10*time.Millisecond, 201, nil)
+	m.ObserveSend(20*time.Millisecond, 201, nil)
+	m.ObserveSend(30*time.Millisecond, 503, errors.New("unexpected status code 503"))
+	m.ObserveSend(40*time.Millisecond, 0, errors.New("connection refused"))
+
+	for status, want := range map[string]float64{"201": 2, "503": 1, "error": 1} {
+		if got := testutil.ToFloat64(m.sends.WithLabelValues(status)); got != want {
+			t.Errorf("sends{status=%q} = %v, want %v", status, got, want)
+		}
+	}
+	if got := testutil.CollectAndCount(m.duration); got != 1 {
+		t.Errorf("duration series = %d, want 1", got)
+	}
+}
+
+func TestMetricsObserveTiming(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+
+	m.ObserveTiming(medic.RequestTiming{DNS: time.Millisecond, Connect: 2 * time.Millisecond, TimeToFirstByte: 9 * time.Millisecond}, 201, nil)
+	m.ObserveTiming(medic.RequestTiming{ConnReused: true, TimeToFirstByte: 5 * time.Millisecond}, 201, nil)
+	m.ObserveTiming(medic.RequestTiming{DNS: time.Millisecond}, 0, errors.New("connection refused"))
+
+	for reused, want := range map[string]float64{"true": 1, "false": 1} {
+		if got := testutil.ToFloat64(m.connections.WithLabelValues(reused)); got != want {
+			t.Errorf("connections{reused=%q} = %v, want %v", reused, got, want)
+		}
+	}
+	// dns, connect and ttfb were observed; tls never happened
+	if got := testutil.CollectAndCount(m.phases); got != 3 {
+		t.Errorf("phase series = %d, want 3", got)
+	}
+}
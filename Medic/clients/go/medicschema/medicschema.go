@@ -0,0 +1,218 @@
+// Package medicschema validates heartbeats against the JSON schema a Medic
+// server publishes, so drift between client and server versions fails
+// locally with the offending fields instead of as a 400.
+//
+// It lives in its own package so the core client has no JSON schema
+// dependency unless validation is wired in:
+//
+//	client := medic.NewClientWithOptions(medic.WithBodyValidator(medicschema.New()))
+package medicschema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Path is where Medic publishes the JSON schema of heartbeat payloads
+const Path = "/schema"
+
+// DefaultTTL is how long a fetched schema is used before it is fetched
+// again, unless WithTTL sets another
+const DefaultTTL = 10 * time.Minute
+
+// DefaultFetchTimeout bounds each schema fetch, unless WithFetchTimeout sets
+// another
+const DefaultFetchTimeout = 10 * time.Second
+
+// ErrMismatch is returned when a heartbeat body does not match the server's
+// published schema. The error also matches a *medic.ValidationError listing
+// each violation
+var ErrMismatch = errors.New("heartbeat does not match the server schema")
+
+// Validator implements medic.BodyValidator against the schema each server
+// publishes at Path. The schema is fetched on first use and cached per
+// server for the TTL. If it can't be fetched or compiled, a warning is
+// logged and bodies go unchecked, or are checked against the last schema
+// fetched, until the TTL passes again
+type Validator struct {
+	ttl          time.Duration
+	fetchTimeout time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+var _ medic.BodyValidator = (*Validator)(nil)
+
+// entry is one server's schema, which is nil when none was ever fetched
+type entry struct {
+	schema  *jsonschema.Schema
+	fetched time.Time
+	// fetching is closed when the fetch in flight ends, and nil otherwise
+	fetching chan struct{}
+}
+
+// Option configures a Validator
+type Option func(*Validator)
+
+// WithTTL sets how long a fetched schema is used before it is fetched
+// again. A non-positive d uses DefaultTTL
+func WithTTL(d time.Duration) Option {
+	return func(v *Validator) {
+		v.ttl = d
+	}
+}
+
+// WithFetchTimeout bounds each schema fetch, retries included. A
+// non-positive d uses DefaultFetchTimeout
+func WithFetchTimeout(d time.Duration) Option {
+	return func(v *Validator) {
+		v.fetchTimeout = d
+	}
+}
+
+// New returns a Validator for medic.WithBodyValidator
+func New(opts ...Option) *Validator {
+	v := &Validator{entries: map[string]*entry{}}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.ttl <= 0 {
+		v.ttl = DefaultTTL
+	}
+	if v.fetchTimeout <= 0 {
+		v.fetchTimeout = DefaultFetchTimeout
+	}
+	return v
+}
+
+// ValidateBody checks body, a JSON heartbeat, against the schema of the
+// server at target
+func (v *Validator) ValidateBody(ctx context.Context, target medic.BodyTarget, body []byte) error {
+	schema := v.schema(ctx, target)
+	if schema == nil {
+		return nil
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: body is not JSON: %w", ErrMismatch, err)
+	}
+	err = schema.Validate(inst)
+	var ve *jsonschema.ValidationError
+	if errors.As(err, &ve) {
+		fields := violations(ve, nil)
+		return fmt.Errorf("%w: %w", ErrMismatch, &medic.ValidationError{Field: fields[0].Field, Message: fields[0].Message, Fields: fields})
+	}
+	return err
+}
+
+// schema returns the compiled schema of target's server, fetching it when
+// it is missing or older than the TTL. Concurrent sends share one fetch, and
+// only wait for it when there is no earlier schema to use. It returns nil
+// when no schema could ever be fetched
+func (v *Validator) schema(ctx context.Context, target medic.BodyTarget) *jsonschema.Schema {
+	url := target.BaseURL + Path
+	v.mu.Lock()
+	e, ok := v.entries[url]
+	if !ok {
+		e = &entry{}
+		v.entries[url] = e
+	}
+	stale := e.fetched.IsZero() || target.Now().Sub(e.fetched) >= v.ttl
+	if stale && e.fetching == nil {
+		e.fetching = make(chan struct{})
+		v.mu.Unlock()
+		v.refresh(ctx, target, url, e)
+		v.mu.Lock()
+	}
+	schema, fetching := e.schema, e.fetching
+	v.mu.Unlock()
+	if schema != nil || fetching == nil {
+		return schema
+	}
+
+	select {
+	case <-fetching:
+	case <-ctx.Done():
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return e.schema
+}
+
+// refresh fetches the schema at url into e. The fetch is detached from the
+// caller's cancellation, since other sends wait on it, and bounded by the
+// fetch timeout instead
+func (v *Validator) refresh(ctx context.Context, target medic.BodyTarget, url string, e *entry) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), v.fetchTimeout)
+	defer cancel()
+	schema, err := fetch(ctx, target, url)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	close(e.fetching)
+	e.fetching = nil
+	switch {
+	case err == nil:
+		e.schema = schema
+		e.fetched = target.Now()
+		return
+	case errors.Is(err, context.Canceled) || medic.IsTimeout(err):
+		// A fetch that ran out of time is tried again on the next send
+	default:
+		// Any other failure is retried once the TTL passes, not on every send
+		e.fetched = target.Now()
+	}
+	target.Logger().Warn("Medic schema unavailable, not validating heartbeats against it",
+		slog.String("url", url),
+		slog.Any("error", err),
+	)
+}
+
+// fetch downloads and compiles the schema at url
+func fetch(ctx context.Context, target medic.BodyTarget, url string) (*jsonschema.Schema, error) {
+	body, err := target.Get(ctx, Path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("schema is not JSON: %w", err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(url, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(url)
+}
+
+// printer renders schema violations in English
+var printer = message.NewPrinter(language.English)
+
+// violations flattens a schema validation error into one FieldError per
+// leaf violation, named by the JSON path of the offending value
+func violations(ve *jsonschema.ValidationError, out []medic.FieldError) []medic.FieldError {
+	if len(ve.Causes) == 0 {
+		field := strings.Join(ve.InstanceLocation, ".")
+		if field == "" {
+			field = "heartbeat"
+		}
+		return append(out, medic.FieldError{Field: field, Message: ve.ErrorKind.LocalizedString(printer)})
+	}
+	for _, cause := range ve.Causes {
+		out = violations(cause, out)
+	}
+	return out
+}
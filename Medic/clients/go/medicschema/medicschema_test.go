@@ -0,0 +1,221 @@
+package medicschema
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+// heartbeatSchema allows only UP and DOWN, like an older server that
+// predates DEGRADED
+const heartbeatSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["heartbeat_name"],
+	"properties": {
+		"heartbeat_name": {"type": "string"},
+		"status": {"enum": ["UP", "DOWN"]},
+		"metadata": {"type": "object", "additionalProperties": {"type": "string", "maxLength": 8}}
+	}
+}`
+
+// schemaServer serves heartbeatSchema, or a 404 when missing is set, and
+// counts schema fetches and heartbeat posts. The first stall fetches hang
+// until the client gives up
+type schemaServer struct {
+	missing        bool
+	stall          int64
+	fetches, posts atomic.Int64
+}
+
+func (s *schemaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == Path {
+		if s.fetches.Add(1) <= s.stall {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		if s.missing {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(heartbeatSchema))
+		return
+	}
+	s.posts.Add(1)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// testClock is a medic.Clock that only moves when advanced, and whose
+// timers fire immediately
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+var (
+	up       = medic.Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: medic.StatusUp}
+	degraded = medic.Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: medic.StatusDegraded}
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name      string
+		h         medic.Heartbeat
+		wantField string
+	}{
+		{name: "testing matching heartbeat", h: up},
+		{name: "testing status the server doesn't know", h: degraded, wantField: "status"},
+		{name: "testing nested violation", h: medic.Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Metadata: map[string]string{"region": "ap-southeast-2"}}, wantField: "metadata.region"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &schemaServer{}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+			c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithBodyValidator(New()))
+
+			err := c.SendHeartbeat(tt.h)
+			if tt.wantField == "" {
+				if err != nil || srv.posts.Load() != 1 {
+					t.Errorf("SendHeartbeat() error = %v with %d posts, want nil and 1", err, srv.posts.Load())
+				}
+				return
+			}
+			var ve *medic.ValidationError
+			if !errors.Is(err, ErrMismatch) || !errors.As(err, &ve) || ve.Field != tt.wantField {
+				t.Errorf("SendHeartbeat() error = %v, want ErrMismatch on %s", err, tt.wantField)
+			}
+			if srv.posts.Load() != 0 {
+				t.Errorf("server got %d posts, want none", srv.posts.Load())
+			}
+		})
+	}
+
+	t.Run("testing schema cached for the TTL", func(t *testing.T) {
+		srv := &schemaServer{}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithClock(clock), medic.WithBodyValidator(New(WithTTL(time.Minute))))
+		for range 3 {
+			c.SendHeartbeat(up)
+		}
+		if srv.fetches.Load() != 1 {
+			t.Errorf("schema fetched %d times, want 1", srv.fetches.Load())
+		}
+		clock.Advance(time.Minute)
+		c.SendHeartbeat(up)
+		if srv.fetches.Load() != 2 {
+			t.Errorf("schema fetched %d times after the TTL, want 2", srv.fetches.Load())
+		}
+	})
+
+	t.Run("testing concurrent sends share one fetch", func(t *testing.T) {
+		srv := &schemaServer{}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithBodyValidator(New()))
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				if err := c.SendHeartbeat(degraded); !errors.Is(err, ErrMismatch) {
+					t.Errorf("SendHeartbeat() error = %v, want ErrMismatch", err)
+				}
+			})
+		}
+		wg.Wait()
+		if srv.fetches.Load() != 1 {
+			t.Errorf("schema fetched %d times, want 1", srv.fetches.Load())
+		}
+	})
+
+	t.Run("testing unavailable schema sends unchecked", func(t *testing.T) {
+		srv := &schemaServer{missing: true}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithBodyValidator(New()), medic.WithSilentLogging())
+		for range 2 {
+			if err := c.SendHeartbeat(degraded); err != nil {
+				t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+			}
+		}
+		if srv.fetches.Load() != 1 || srv.posts.Load() != 2 {
+			t.Errorf("schema fetched %d times with %d posts, want 1 and 2", srv.fetches.Load(), srv.posts.Load())
+		}
+	})
+
+	t.Run("testing timed out fetch is not cached", func(t *testing.T) {
+		srv := &schemaServer{stall: 1}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithBodyValidator(New(WithFetchTimeout(50*time.Millisecond))), medic.WithSilentLogging())
+		if err := c.SendHeartbeat(degraded); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v, want it sent unchecked", err)
+		}
+		if err := c.SendHeartbeat(degraded); !errors.Is(err, ErrMismatch) {
+			t.Errorf("SendHeartbeat() error = %v, want ErrMismatch once the schema is fetched again", err)
+		}
+		if srv.fetches.Load() != 2 {
+			t.Errorf("schema fetched %d times, want 2", srv.fetches.Load())
+		}
+	})
+
+	t.Run("testing cancelled caller still caches the schema", func(t *testing.T) {
+		srv := &schemaServer{}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithBodyValidator(New()))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c.SendHeartbeatContext(ctx, up)
+		if err := c.SendHeartbeat(degraded); !errors.Is(err, ErrMismatch) {
+			t.Errorf("SendHeartbeat() error = %v, want ErrMismatch", err)
+		}
+		if srv.fetches.Load() != 1 {
+			t.Errorf("schema fetched %d times, want 1", srv.fetches.Load())
+		}
+	})
+
+	t.Run("testing batch", func(t *testing.T) {
+		srv := &schemaServer{}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		c := medic.NewClientWithOptions(medic.WithBaseURL(ts.URL), medic.WithBodyValidator(New()))
+		err := c.SendHeartbeats([]medic.Heartbeat{up, degraded, up})
+		var be *medic.BatchError
+		if !errors.As(err, &be) || len(be.Invalid) != 1 || !errors.Is(be.Invalid[1], ErrMismatch) {
+			t.Errorf("SendHeartbeats() error = %v, want index 1 to mismatch the schema", err)
+		}
+		if srv.posts.Load() != 0 {
+			t.Errorf("server got %d posts, want none", srv.posts.Load())
+		}
+	})
+}
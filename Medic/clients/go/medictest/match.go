@@ -0,0 +1,115 @@
+package medictest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+// Match describes the heartbeat an assertion looks for. Zero fields match
+// anything, so a Match can check as few fields as a test cares about
+type Match struct {
+	Name    string
+	Service string
+	Status  medic.Status
+	// Metadata entries must all be present with these values; other keys
+	// on the heartbeat are ignored
+	Metadata map[string]string
+}
+
+// Matches reports whether h has every field set in m
+func (m Match) Matches(h medic.Heartbeat) bool {
+	return len(m.diff(h)) == 0
+}
+
+// String describes the fields m checks, such as {Name: "my-hb", Status: UP}
+func (m Match) String() string {
+	var parts []string
+	if m.Name != "" {
+		parts = append(parts, fmt.Sprintf("Name: %q", m.Name))
+	}
+	if m.Service != "" {
+		parts = append(parts, fmt.Sprintf("Service: %q", m.Service))
+	}
+	if m.Status != "" {
+		parts = append(parts, fmt.Sprintf("Status: %s", m.Status))
+	}
+	for _, k := range sortedKeys(m.Metadata) {
+		parts = append(parts, fmt.Sprintf("Metadata[%q]: %q", k, m.Metadata[k]))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// diff lists how h differs from m, or nothing when it matches
+func (m Match) diff(h medic.Heartbeat) []string {
+	var diffs []string
+	if m.Name != "" && h.HeartbeatName != m.Name {
+		diffs = append(diffs, fmt.Sprintf("name %q, want %q", h.HeartbeatName, m.Name))
+	}
+	if m.Service != "" && h.Service != m.Service {
+		diffs = append(diffs, fmt.Sprintf("service %q, want %q", h.Service, m.Service))
+	}
+	if m.Status != "" && h.Status != m.Status {
+		diffs = append(diffs, fmt.Sprintf("status %s, want %s", h.Status, m.Status))
+	}
+	for _, k := range sortedKeys(m.Metadata) {
+		if v, ok := h.Metadata[k]; !ok {
+			diffs = append(diffs, fmt.Sprintf("metadata %q missing, want %q", k, m.Metadata[k]))
+		} else if v != m.Metadata[k] {
+			diffs = append(diffs, fmt.Sprintf("metadata %q = %q, want %q", k, v, m.Metadata[k]))
+		}
+	}
+	return diffs
+}
+
+// Sent returns the recorded heartbeats that match m, in the order received
+func (s *Server) Sent(m Match) []medic.Heartbeat {
+	var matched []medic.Heartbeat
+	for _, h := range s.Heartbeats() {
+		if m.Matches(h) {
+			matched = append(matched, h)
+		}
+	}
+	return matched
+}
+
+// AssertSent fails t unless a heartbeat matching m was recorded. The failure
+// lists every recorded heartbeat and how it differs from m
+func (s *Server) AssertSent(t testing.TB, m Match) {
+	t.Helper()
+	if len(s.Sent(m)) > 0 {
+		return
+	}
+	hs := s.Heartbeats()
+	if len(hs) == 0 {
+		t.Errorf("no heartbeat matching %s was sent; none were received", m)
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "no heartbeat matching %s was sent; received %d:", m, len(hs))
+	for _, h := range hs {
+		fmt.Fprintf(&b, "\n\t%s: %s", h, strings.Join(m.diff(h), "; "))
+	}
+	t.Error(b.String())
+}
+
+// AssertNotSent fails t if any heartbeat matching m was recorded
+func (s *Server) AssertNotSent(t testing.TB, m Match) {
+	t.Helper()
+	if matched := s.Sent(m); len(matched) > 0 {
+		t.Errorf("%d heartbeats matching %s were sent, want none; first was %s", len(matched), m, matched[0])
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,75 @@
+package medictest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+// recordingT captures the failures of an assertion under test
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestAssertSent(t *testing.T) {
+	srv := NewTestServer()
+	defer srv.Close()
+	client := srv.Client()
+	for _, h := range []medic.Heartbeat{
+		{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fake-service", Status: medic.StatusUp, Metadata: map[string]string{"region": "eu", "version": "1.2"}},
+		{HeartbeatName: "staging-other-hb", Status: medic.StatusDown},
+	} {
+		if err := client.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		match    Match
+		wantFail string
+	}{
+		{name: "testing name and status", match: Match{Name: "staging-fake-heartbeat-hb", Status: medic.StatusUp}},
+		{name: "testing partial metadata", match: Match{Service: "fake-service", Metadata: map[string]string{"region": "eu"}}},
+		{name: "testing empty match", match: Match{}},
+		{name: "testing wrong status", match: Match{Name: "staging-other-hb", Status: medic.StatusUp}, wantFail: "staging-other-hb=DOWN: status DOWN, want UP"},
+		{name: "testing wrong metadata", match: Match{Metadata: map[string]string{"region": "us"}}, wantFail: `metadata "region" = "eu", want "us"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			srv.AssertSent(rt, tt.match)
+			if tt.wantFail == "" {
+				if len(rt.errors) > 0 {
+					t.Errorf("AssertSent() failed: %v", rt.errors)
+				}
+				return
+			}
+			if len(rt.errors) != 1 || !strings.Contains(rt.errors[0], tt.wantFail) {
+				t.Errorf("AssertSent() errors = %q, want one containing %q", rt.errors, tt.wantFail)
+			}
+		})
+	}
+
+	t.Run("testing AssertNotSent", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		srv.AssertNotSent(rt, Match{Status: medic.StatusDegraded})
+		srv.AssertNotSent(rt, Match{Status: medic.StatusDown})
+		if len(rt.errors) != 1 {
+			t.Errorf("AssertNotSent() errors = %q, want one for the DOWN heartbeat", rt.errors)
+		}
+	})
+}
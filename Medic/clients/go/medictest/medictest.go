@@ -0,0 +1,163 @@
+// Package medictest provides a fake Medic server for testing code that sends
+// heartbeats, so callers don't have to hand-roll an httptest.Server:
+//
+//	srv := medictest.NewTestServer()
+//	defer srv.Close()
+//
+//	client := medic.NewClient(srv.URL)
+//	// ... exercise code that sends heartbeats with client ...
+//
+//	if h, ok := srv.LastHeartbeat(); !ok || h.Status != medic.StatusUp {
+//		t.Errorf("last heartbeat = %+v, want UP", h)
+//	}
+package medictest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+// Server is a running fake Medic endpoint that records the heartbeats it receives
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	heartbeats []medic.Heartbeat
+	failures   []int
+}
+
+// NewTestServer starts a fake Medic server. It accepts POST /heartbeat and
+// POST /heartbeat/batch, answers GET /health, and responds 404 to anything
+// else. Call Close when done
+func NewTestServer() *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Client returns a client pointed at the server, configured by opts
+func (s *Server) Client(opts ...medic.Option) *medic.Client {
+	return medic.NewClientWithOptions(append([]medic.Option{medic.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// FailNext makes the next request fail with statusCode. Calls queue up, so
+// calling it twice fails the next two requests. Clients retry 5xx and 429
+// responses, so a single failure is usually followed by a recorded success
+func (s *Server) FailNext(statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statusCode)
+}
+
+// LastHeartbeat returns the most recently recorded heartbeat, and false if none was received
+func (s *Server) LastHeartbeat() (medic.Heartbeat, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.heartbeats) == 0 {
+		return medic.Heartbeat{}, false
+	}
+	return s.heartbeats[len(s.heartbeats)-1], true
+}
+
+// Heartbeats returns every recorded heartbeat in the order received
+func (s *Server) Heartbeats() []medic.Heartbeat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]medic.Heartbeat(nil), s.heartbeats...)
+}
+
+// Count returns the number of heartbeats recorded; each heartbeat in a batch counts once
+func (s *Server) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.heartbeats)
+}
+
+// Reset clears recorded heartbeats and pending failures
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heartbeats = nil
+	s.failures = nil
+}
+
+// nextFailure pops the next queued failure status code, or returns 0
+func (s *Server) nextFailure() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.failures) == 0 {
+		return 0
+	}
+	code := s.failures[0]
+	s.failures = s.failures[1:]
+	return code
+}
+
+// record appends received heartbeats
+func (s *Server) record(hs ...medic.Heartbeat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.heartbeats = append(s.heartbeats, hs...)
+}
+
+// handle serves the fake Medic API
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if code := s.nextFailure(); code != 0 {
+		writeEnvelope(w, code, false, http.StatusText(code))
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/health":
+		writeEnvelope(w, http.StatusOK, true, "healthy")
+	case r.Method == http.MethodPost && r.URL.Path == "/heartbeat":
+		var h medic.Heartbeat
+		if err := decodeBody(r, &h); err != nil {
+			writeEnvelope(w, http.StatusBadRequest, false, "Invalid request body.")
+			return
+		}
+		s.record(h)
+		writeEnvelope(w, http.StatusCreated, true, "Heartbeat Posted Successfully.")
+	case r.Method == http.MethodPost && r.URL.Path == "/heartbeat/batch":
+		var hs []medic.Heartbeat
+		if err := decodeBody(r, &hs); err != nil {
+			writeEnvelope(w, http.StatusBadRequest, false, "Invalid request body.")
+			return
+		}
+		s.record(hs...)
+		writeEnvelope(w, http.StatusCreated, true, "Heartbeats Posted Successfully.")
+	default:
+		writeEnvelope(w, http.StatusNotFound, false, "Not found.")
+	}
+}
+
+// decodeBody decodes a JSON request body, gunzipping it if needed
+func decodeBody(r *http.Request, v any) error {
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		body = zr
+	}
+	return json.NewDecoder(body).Decode(v)
+}
+
+// writeEnvelope writes a standard Medic response envelope
+func writeEnvelope(w http.ResponseWriter, code int, success bool, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{
+		"success": success,
+		"message": message,
+		"results": "",
+	})
+}
@@ -0,0 +1,55 @@
+package medictest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	medic "github.com/linq-team/medic/Medic/clients/go"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewTestServer()
+	defer srv.Close()
+	client := srv.Client(medic.WithRetry(medic.RetryConfig{MaxAttempts: 1}))
+
+	if _, ok := srv.LastHeartbeat(); ok {
+		t.Fatal("LastHeartbeat() ok = true before any heartbeat was sent")
+	}
+
+	h := medic.Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fake-service", Status: medic.StatusUp}
+	if err := client.SendHeartbeat(h); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if got, ok := srv.LastHeartbeat(); !ok || !reflect.DeepEqual(got, h) {
+		t.Errorf("LastHeartbeat() = %+v, %v, want %+v", got, ok, h)
+	}
+
+	batch := []medic.Heartbeat{h, {HeartbeatName: "staging-fake-heartbeat-hb-2", Status: medic.StatusDown}}
+	if err := client.SendHeartbeats(batch); err != nil {
+		t.Fatalf("SendHeartbeats() unexpected error = %v", err)
+	}
+	if got := srv.Count(); got != 3 {
+		t.Errorf("Count() = %d, want 3", got)
+	}
+
+	srv.FailNext(http.StatusServiceUnavailable)
+	var se *medic.StatusError
+	if err := client.SendHeartbeat(h); !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("SendHeartbeat() error = %v, want *StatusError with code 503", err)
+	}
+	if got := srv.Count(); got != 3 {
+		t.Errorf("Count() after failure = %d, want 3", got)
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() unexpected error = %v", err)
+	}
+
+	srv.Reset()
+	if got := srv.Count(); got != 0 {
+		t.Errorf("Count() after Reset = %d, want 0", got)
+	}
+}
@@ -0,0 +1,24 @@
+package medic
+
+import "time"
+
+// Metrics observes the outcome of every request attempt, including retries.
+// The medicprom subpackage provides a Prometheus implementation
+type Metrics interface {
+	// ObserveSend records one attempt. statusCode is 0 when no response was received
+	ObserveSend(duration time.Duration, statusCode int, err error)
+}
+
+// WithMetrics reports every request attempt to m
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) {
+		c.Metrics = m
+	}
+}
+
+// observe reports an attempt to the configured metrics hook, if any
+func (c *Client) observe(start time.Time, statusCode int, err error) {
+	if c.Metrics != nil {
+		c.Metrics.ObserveSend(time.Since(start), statusCode, err)
+	}
+}
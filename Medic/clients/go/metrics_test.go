@@ -0,0 +1,101 @@
+package medic
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu    sync.Mutex
+	codes []int
+}
+
+func (m *recordingMetrics) ObserveSend(duration time.Duration, statusCode int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.codes = append(m.codes, statusCode)
+}
+
+func TestMetricsObservesEachAttempt(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	m := &recordingMetrics{}
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithMetrics(m),
+		WithRetry(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if len(m.codes) != 2 || m.codes[0] != 503 || m.codes[1] != 201 {
+		t.Errorf("observed codes = %v, want [503 201]", m.codes)
+	}
+}
+
+type timingMetrics struct {
+	recordingMetrics
+	timings []RequestTiming
+}
+
+func (m *timingMetrics) ObserveTiming(timing RequestTiming, statusCode int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timings = append(m.timings, timing)
+}
+
+func TestTimingMetrics(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	m := &timingMetrics{}
+	hc := srv.Client()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithHTTPClient(hc), WithMetrics(m))
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	for range 2 {
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+	}
+	if len(m.timings) != 2 {
+		t.Fatalf("observed %d timings, want 2", len(m.timings))
+	}
+
+	tests := []struct {
+		name       string
+		timing     RequestTiming
+		wantReused bool
+	}{
+		{name: "testing new connection", timing: m.timings[0]},
+		{name: "testing reused connection", timing: m.timings[1], wantReused: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.timing
+			if got.ConnReused != tt.wantReused {
+				t.Errorf("ConnReused = %v, want %v", got.ConnReused, tt.wantReused)
+			}
+			if dialed := got.Connect > 0 && got.TLSHandshake > 0; dialed == tt.wantReused {
+				t.Errorf("Connect = %v, TLSHandshake = %v, want them set only on a new connection", got.Connect, got.TLSHandshake)
+			}
+			if got.TimeToFirstByte < 20*time.Millisecond || got.Total < got.TimeToFirstByte {
+				t.Errorf("TimeToFirstByte = %v, Total = %v, want 20ms <= TimeToFirstByte <= Total", got.TimeToFirstByte, got.Total)
+			}
+		})
+	}
+}
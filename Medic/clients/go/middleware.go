@@ -0,0 +1,39 @@
+package medic
+
+import "net/http"
+
+// Middleware wraps a RoundTripper to add behavior such as request signing or
+// body logging around every request the client makes
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// WithTransportMiddleware wraps the client's transport with the given
+// middleware. The first middleware is outermost and sees each request first.
+// Middleware is applied after all other options, so it always wraps the final
+// transport, including any proxy or TLS settings, whatever the option order
+func WithTransportMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// applyMiddleware wraps a copy of the client's transport in its middleware
+// chain and recorder
+func (c *Client) applyMiddleware() {
+	if len(c.middleware) == 0 && c.recorder == nil {
+		return
+	}
+	hc := *c.httpClient()
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	// The recorder is innermost so it sees requests as middleware leaves them
+	if c.recorder != nil {
+		rt = c.recorder.wrap(rt)
+	}
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+	hc.Transport = rt
+	c.HTTPClient = &hc
+}
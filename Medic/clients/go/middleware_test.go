@@ -0,0 +1,89 @@
+package medic
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithTransportMiddleware(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, "server:"+r.Header.Get("X-Signature"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				seen = append(seen, name)
+				r.Header.Set("X-Signature", r.Header.Get("X-Signature")+name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+
+	// Middleware listed before WithoutProxy still wraps the configured transport
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithTransportMiddleware(tag("a"), tag("b")),
+		WithoutProxy(),
+	)
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if want := []string{"a", "b", "server:ab"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("call order = %v, want %v", seen, want)
+	}
+}
+
+func TestWithRequestEditor(t *testing.T) {
+	var calls int
+	var got http.Header
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		got, path = r.Header.Clone(), r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	t.Run("testing editors run in order", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithRequestEditor(
+			func(req *http.Request) error {
+				req.Header.Set("X-Order", "first")
+				req.URL.Path = "/v2" + req.URL.Path
+				return nil
+			},
+			func(req *http.Request) error {
+				req.Header.Add("X-Order", "second")
+				return nil
+			},
+		))
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+		if want := []string{"first", "second"}; !reflect.DeepEqual(got["X-Order"], want) {
+			t.Errorf("X-Order = %v, want %v", got["X-Order"], want)
+		}
+		if path != "/v2/heartbeat" {
+			t.Errorf("path = %q, want /v2/heartbeat", path)
+		}
+	})
+
+	t.Run("testing editor error aborts send", func(t *testing.T) {
+		calls = 0
+		errSign := errors.New("no signing key")
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithRequestEditor(func(req *http.Request) error { return errSign }))
+		if err := c.SendHeartbeat(h); !errors.Is(err, errSign) {
+			t.Errorf("SendHeartbeat() error = %v, want %v", err, errSign)
+		}
+		if calls != 0 {
+			t.Errorf("server received %d requests, want 0", calls)
+		}
+	})
+}
@@ -0,0 +1,223 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrMonitorRunning is returned when Start is called on a Monitor that is already running
+var ErrMonitorRunning = errors.New("monitor is already running")
+
+// ErrMonitorNotRunning is returned when Update or SetStatus is called on a
+// Monitor that is not running
+var ErrMonitorNotRunning = errors.New("monitor is not running")
+
+// FinalSendTimeout bounds the final heartbeat sent by StopWithStatus
+const FinalSendTimeout = 5 * time.Second
+
+// Monitor sends a heartbeat on a fixed interval in the background
+type Monitor struct {
+	client *Client
+
+	// OnError is called after each failed send. Failures never stop the monitor
+	OnError func(h Heartbeat, err error)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	// h is the heartbeat sent on each tick, swapped by Update and kept for
+	// StopWithStatus
+	h Heartbeat
+
+	debounce statusDebounce
+	jitter   monitorJitter
+}
+
+// NewMonitor creates a Monitor that sends heartbeats with the given client,
+// or with the default client when c is nil
+func NewMonitor(c *Client, opts ...MonitorOption) *Monitor {
+	if c == nil {
+		c = defaultClient()
+	}
+	m := &Monitor{client: c}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start sends h immediately and then every interval until ctx is cancelled
+// or Stop is called. It returns without waiting for any send. If h has no
+// Interval set, it is sent with the monitor's interval
+func (m *Monitor) Start(ctx context.Context, h Heartbeat, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("monitor interval must be positive, got %v", interval)
+	}
+	if h.Interval == 0 {
+		h.Interval = interval
+	}
+	if err := m.client.validate(h); err != nil {
+		return fmt.Errorf("invalid heartbeat: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done != nil {
+		return ErrMonitorRunning
+	}
+	if err := m.client.addMonitor(m); err != nil {
+		return err
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.done = make(chan struct{})
+	m.h = h
+	go m.run(ctx, interval, m.done)
+	return nil
+}
+
+// Update swaps the heartbeat the running monitor sends from the next tick
+// on, for example to report StatusDegraded under load. If h has no Interval
+// set, the current one is kept. It is safe to call while the monitor runs
+func (m *Monitor) Update(h Heartbeat) error {
+	return m.update(func(current Heartbeat) Heartbeat {
+		if h.Interval == 0 {
+			h.Interval = current.Interval
+		}
+		return h
+	})
+}
+
+// SetStatus changes only the status the running monitor sends from the next
+// tick on. It is safe to call while the monitor runs
+func (m *Monitor) SetStatus(status Status) error {
+	return m.update(func(current Heartbeat) Heartbeat {
+		current.Status = status
+		return current
+	})
+}
+
+// update validates next(current heartbeat) and swaps it in, all under the
+// lock so concurrent updates can't overwrite each other
+func (m *Monitor) update(next func(Heartbeat) Heartbeat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done == nil {
+		return ErrMonitorNotRunning
+	}
+	h := next(m.h)
+	if err := m.client.validate(h); err != nil {
+		return fmt.Errorf("invalid heartbeat: %w", err)
+	}
+	m.h = h
+	return nil
+}
+
+// current returns the heartbeat to send on this tick
+func (m *Monitor) current() Heartbeat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.h
+}
+
+// Stop halts the monitor and waits for any in-flight send to finish
+func (m *Monitor) Stop() {
+	m.stop()
+}
+
+// StopWithStatus halts the monitor like Stop, then sends one final heartbeat
+// with the given status, typically StatusDown, so Medic learns of a graceful
+// shutdown right away instead of waiting for the heartbeat to go stale. The
+// final send is bounded by FinalSendTimeout; its error is reported to OnError
+// and returned, and never delays shutdown beyond the timeout. If the monitor
+// was not running, nothing is sent
+func (m *Monitor) StopWithStatus(status Status) error {
+	h, ok := m.stop()
+	if !ok {
+		return nil
+	}
+	h.Status = status
+	// A deliberate final status is never treated as flapping
+	m.debounce.mu.Lock()
+	m.debounce.record(h, m.client.clock().Now())
+	m.debounce.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), FinalSendTimeout)
+	defer cancel()
+	err := m.client.SendHeartbeatContext(ctx, h)
+	if err != nil && m.OnError != nil {
+		m.OnError(h, err)
+	}
+	return err
+}
+
+// stop cancels the loop and waits for it to exit, returning the heartbeat it
+// was sending and whether it was running
+func (m *Monitor) stop() (Heartbeat, bool) {
+	m.mu.Lock()
+	cancel, done, h := m.cancel, m.done, m.h
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return Heartbeat{}, false
+	}
+	cancel()
+	<-done
+	m.client.removeMonitor(m)
+	return h, true
+}
+
+// run is the ticker loop started by Start. With tick jitter, each wait is
+// drawn separately and timed by the client's clock instead of a ticker
+func (m *Monitor) run(ctx context.Context, interval time.Duration, done chan struct{}) {
+	defer close(done)
+	defer m.finish(done)
+
+	clock := m.client.clock()
+	if delay := m.jitter.startDelay(interval); delay > 0 {
+		if sleepContext(ctx, clock, delay) != nil {
+			return
+		}
+	}
+	if m.jitter.tick > 0 {
+		for {
+			m.send(ctx, m.current())
+			if sleepContext(ctx, clock, m.jitter.tickWait(interval)) != nil {
+				return
+			}
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.send(ctx, m.current())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// finish clears the running state when the loop exits on its own, so the
+// monitor can be started again after its context is cancelled
+func (m *Monitor) finish(done chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done == done {
+		m.cancel()
+		m.cancel, m.done = nil, nil
+		m.client.removeMonitor(m)
+	}
+}
+
+// send delivers one heartbeat, reporting failures to OnError
+func (m *Monitor) send(ctx context.Context, h Heartbeat) {
+	err := m.Send(ctx, h)
+	if err != nil && ctx.Err() == nil && m.OnError != nil {
+		m.OnError(h, err)
+	}
+}
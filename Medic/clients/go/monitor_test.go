@@ -0,0 +1,244 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMonitor(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the second beat to prove the loop survives errors
+		if atomic.AddInt32(&calls, 1) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var failures int32
+	m := NewMonitor(NewClient(srv.URL))
+	m.OnError = func(h Heartbeat, err error) { atomic.AddInt32(&failures, 1) }
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}
+	if err := m.Start(context.Background(), h, 20*time.Millisecond); err != nil {
+		t.Fatalf("Start() unexpected error = %v", err)
+	}
+	if err := m.Start(context.Background(), h, 20*time.Millisecond); err != ErrMonitorRunning {
+		t.Errorf("second Start() error = %v, want ErrMonitorRunning", err)
+	}
+	time.Sleep(70 * time.Millisecond)
+	m.Stop()
+
+	sent := atomic.LoadInt32(&calls)
+	if sent < 3 {
+		t.Errorf("monitor sent %d heartbeats, want at least 3", sent)
+	}
+	if atomic.LoadInt32(&failures) != 1 {
+		t.Errorf("OnError called %d times, want 1", failures)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if atomic.LoadInt32(&calls) != sent {
+		t.Errorf("monitor kept sending after Stop()")
+	}
+}
+
+func TestMonitorSendsImmediately(t *testing.T) {
+	sent := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		select {
+		case sent <- struct{}{}:
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMonitor(NewClient(srv.URL))
+	if err := m.Start(ctx, Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}, time.Hour); err != nil {
+		t.Fatalf("Start() unexpected error = %v", err)
+	}
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not send an initial heartbeat")
+	}
+
+	// Cancelling the context stops the monitor and allows a restart
+	cancel()
+	m.Stop()
+	if err := m.Start(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}, time.Hour); err != nil {
+		t.Errorf("restart Start() unexpected error = %v", err)
+	}
+	m.Stop()
+}
+
+func TestMonitorSendsInterval(t *testing.T) {
+	got := make(chan Heartbeat, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var h Heartbeat
+		json.NewDecoder(r.Body).Decode(&h)
+		w.WriteHeader(http.StatusCreated)
+		select {
+		case got <- h:
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	m := NewMonitor(NewClient(srv.URL))
+	if err := m.Start(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb"}, time.Minute); err != nil {
+		t.Fatalf("Start() unexpected error = %v", err)
+	}
+	defer m.Stop()
+
+	select {
+	case h := <-got:
+		if h.Interval != time.Minute {
+			t.Errorf("sent Interval = %v, want the monitor interval of 1m", h.Interval)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not send a heartbeat")
+	}
+}
+
+func TestMonitorStopWithStatus(t *testing.T) {
+	var mu sync.Mutex
+	var statuses []Status
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var h Heartbeat
+		json.NewDecoder(r.Body).Decode(&h)
+		mu.Lock()
+		statuses = append(statuses, h.Status)
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	m := NewMonitor(NewClient(srv.URL))
+	if err := m.StopWithStatus(StatusDown); err != nil {
+		t.Errorf("StopWithStatus() before Start error = %v, want nil", err)
+	}
+	if err := m.Start(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}, time.Minute); err != nil {
+		t.Fatalf("Start() unexpected error = %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if err := m.StopWithStatus(StatusDown); err != nil {
+		t.Fatalf("StopWithStatus() unexpected error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(statuses) != 2 || statuses[0] != StatusUp || statuses[1] != StatusDown {
+		t.Errorf("server received %v, want [UP DOWN]", statuses)
+	}
+}
+
+func TestMonitorJitter(t *testing.T) {
+	const interval = time.Minute
+	const beats = 4
+	run := func(t *testing.T) []time.Duration {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == beats {
+				cancel()
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer srv.Close()
+
+		clock := newFakeClock()
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock))
+		m := NewMonitor(c, WithStartJitter(), WithTickJitter(0.2), WithJitterSeed(42))
+		if err := m.Start(ctx, Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}, interval); err != nil {
+			t.Fatalf("Start() unexpected error = %v", err)
+		}
+		<-ctx.Done()
+		m.Stop()
+		sleeps := clock.Sleeps()
+		if len(sleeps) < beats {
+			t.Fatalf("clock slept %d times, want at least %d", len(sleeps), beats)
+		}
+		return sleeps[:beats]
+	}
+
+	first := run(t)
+	if first[0] < 0 || first[0] >= interval {
+		t.Errorf("start delay = %v, want within [0, %v)", first[0], interval)
+	}
+	for i, d := range first[1:] {
+		if d < interval*8/10 || d > interval*12/10 {
+			t.Errorf("wait %d = %v, want within 20%% of %v", i+1, d, interval)
+		}
+	}
+	if second := run(t); !reflect.DeepEqual(first, second) {
+		t.Errorf("waits with the same seed = %v and %v, want identical", first, second)
+	}
+}
+
+func TestMonitorUpdate(t *testing.T) {
+	received := make(chan Heartbeat, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var h Heartbeat
+		json.NewDecoder(r.Body).Decode(&h)
+		select {
+		case received <- h:
+		default:
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	m := NewMonitor(NewClient(srv.URL))
+	if err := m.SetStatus(StatusDown); err != ErrMonitorNotRunning {
+		t.Errorf("SetStatus() before Start error = %v, want ErrMonitorNotRunning", err)
+	}
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	if err := m.Start(context.Background(), h, 20*time.Millisecond); err != nil {
+		t.Fatalf("Start() unexpected error = %v", err)
+	}
+	defer m.Stop()
+	// A tick already in flight may still carry the old heartbeat
+	next := func(status Status) Heartbeat {
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case got := <-received:
+				if got.Status == status {
+					return got
+				}
+			case <-timeout:
+				t.Fatalf("monitor never sent status %q", status)
+			}
+		}
+	}
+	next(StatusUp)
+
+	if err := m.SetStatus(StatusDegraded); err != nil {
+		t.Fatalf("SetStatus() unexpected error = %v", err)
+	}
+	if got := next(StatusDegraded); got.Interval != 20*time.Millisecond {
+		t.Errorf("after SetStatus sent interval %v, want the monitor interval", got.Interval)
+	}
+
+	if err := m.Update(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusDown, Metadata: map[string]string{"reason": "drain"}}); err != nil {
+		t.Fatalf("Update() unexpected error = %v", err)
+	}
+	if got := next(StatusDown); got.Metadata["reason"] != "drain" {
+		t.Errorf("after Update sent metadata %v, want the updated metadata", got.Metadata)
+	}
+	if err := m.SetStatus("Up"); err == nil {
+		t.Error("SetStatus() with an invalid status expected an error")
+	}
+}
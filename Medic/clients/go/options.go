@@ -0,0 +1,211 @@
+package medic
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created with NewClientWithOptions
+type Option func(*Client)
+
+// NewClientWithOptions creates a new Medic client configured by opts.
+// Options are applied in order; if no base URL is set, it will use the
+// MEDIC_BASE_URL env var or the default, and if no credentials are set it
+// will use the MEDIC_API_TOKEN env var. A unix:// base URL sends requests
+// over that Unix socket, like WithUnixSocket
+func NewClientWithOptions(opts ...Option) *Client {
+	c := &Client{
+		HTTPClient: newHTTPClient(),
+		Retry:      DefaultRetryConfig,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = GetBaseURL()
+	}
+	c.applyUnixBaseURL()
+	c.BaseURL = normalizeBaseURL(c.BaseURL)
+	if err := validateBaseURL(c.BaseURL); err != nil {
+		c.setConfigErr(err)
+	}
+	if c.requireHTTPS {
+		if err := c.checkHTTPS(); err != nil {
+			c.setConfigErr(err)
+		}
+	}
+	c.applyMiddleware()
+	if c.insecureSkipVerify {
+		c.logInsecure()
+	}
+	if c.AuthToken == "" && !c.apiKey {
+		c.AuthToken = GetAPIToken()
+	}
+	return c
+}
+
+// NewClientStrict creates a new Medic client like NewClientWithOptions, but
+// returns an error if the base URL is malformed or any option failed, rather
+// than deferring it to the first request. A malformed MEDIC_TIMEOUT, which
+// GetTimeout only logs, is an error too
+func NewClientStrict(baseURL string, opts ...Option) (*Client, error) {
+	if _, err := parseTimeout(os.Getenv("MEDIC_TIMEOUT")); err != nil {
+		return nil, err
+	}
+	c := NewClientWithOptions(append([]Option{WithBaseURL(baseURL)}, opts...)...)
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+	return c, nil
+}
+
+// normalizeBaseURL strips trailing slashes so paths join without a double slash
+func normalizeBaseURL(baseURL string) string {
+	return strings.TrimRight(strings.TrimSpace(baseURL), "/")
+}
+
+// normalizeBasePath returns basePath with one leading slash and no trailing
+// slash, or "" for an empty or root path
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// validateBaseURL checks that baseURL is an absolute http or https URL
+func validateBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid base URL %q: missing host", baseURL)
+	}
+	return nil
+}
+
+// setConfigErr records the first error raised while applying options
+func (c *Client) setConfigErr(err error) {
+	if c.configErr == nil {
+		c.configErr = err
+	}
+}
+
+// WithBaseURL sets the Medic API base URL
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithBasePath prefixes every endpoint path with basePath, for a Medic server
+// mounted under a path such as "/api/v2/medic" behind a gateway. Leading and
+// trailing slashes are optional
+func WithBasePath(basePath string) Option {
+	return func(c *Client) {
+		c.BasePath = normalizeBasePath(basePath)
+	}
+}
+
+// WithFallbackURLs adds standby Medic servers. When a request to the primary
+// base URL still fails with a connection error, 429, or 5xx after retries, it
+// is retried against each fallback in order with the same path, until one
+// succeeds. Calls with WithRequestBaseURL do not fall back. A malformed URL
+// is returned from the client's first request
+func WithFallbackURLs(urls ...string) Option {
+	return func(c *Client) {
+		for _, u := range urls {
+			u = normalizeBaseURL(u)
+			if err := validateBaseURL(u); err != nil {
+				c.setConfigErr(fmt.Errorf("fallback: %w", err))
+				continue
+			}
+			c.FallbackURLs = append(c.FallbackURLs, u)
+		}
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.HTTPClient = hc
+		}
+	}
+}
+
+// WithTimeout sets the overall request timeout. The current HTTP client is
+// copied rather than modified so clients never share a mutated timeout
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient()
+		hc.Timeout = d
+		c.HTTPClient = &hc
+	}
+}
+
+// WithUserAgent replaces the default User-Agent header sent with every request.
+// To keep the library identifier, append to DefaultUserAgent:
+//
+//	medic.WithUserAgent(medic.DefaultUserAgent + " my-service/1.2.0")
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.UserAgent = ua
+	}
+}
+
+// WithHeader adds a header sent with every request. Content-Type cannot be overridden
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		}
+		c.Headers.Add(key, value)
+	}
+}
+
+// WithContextHeaderFunc derives headers for every request from its context,
+// such as a correlation ID stored there by upstream middleware, so it needn't
+// be passed to each call. fn runs once per attempt and may return nil. Its
+// headers override client headers, and per-call headers override them
+func WithContextHeaderFunc(fn func(context.Context) http.Header) Option {
+	return func(c *Client) {
+		c.ContextHeaderFunc = fn
+	}
+}
+
+// WithBearerToken authenticates requests with "Authorization: Bearer <token>"
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.AuthToken = token
+	}
+}
+
+// WithAPIKey authenticates requests by sending value in the given header,
+// for deployments that use a custom API key scheme
+func WithAPIKey(header, value string) Option {
+	return func(c *Client) {
+		if c.Headers == nil {
+			c.Headers = http.Header{}
+		}
+		c.Headers.Set(header, value)
+		c.apiKey = true
+	}
+}
+
+// WithRetry sets the retry configuration
+func WithRetry(r RetryConfig) Option {
+	return func(c *Client) {
+		c.Retry = r
+	}
+}
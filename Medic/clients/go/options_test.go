@@ -0,0 +1,436 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Setenv("MEDIC_BASE_URL", "")
+
+	c := NewClientWithOptions(WithTimeout(2 * time.Second))
+	if c.BaseURL != DefaultBaseURL {
+		t.Errorf("BaseURL = %q, want %q", c.BaseURL, DefaultBaseURL)
+	}
+	if c.HTTPClient.Timeout != 2*time.Second {
+		t.Errorf("Timeout = %v, want 2s", c.HTTPClient.Timeout)
+	}
+	if other := NewClient(""); other.HTTPClient == c.HTTPClient || other.HTTPClient.Timeout != DefaultTimeout {
+		t.Errorf("clients share an HTTP client or WithTimeout leaked: timeout = %v", other.HTTPClient.Timeout)
+	}
+
+	hc := &http.Client{}
+	c = NewClientWithOptions(WithBaseURL("https://custom-medic.example.com"), WithHTTPClient(hc))
+	if c.BaseURL != "https://custom-medic.example.com" || c.HTTPClient != hc {
+		t.Errorf("options not applied: BaseURL = %q, HTTPClient = %p", c.BaseURL, c.HTTPClient)
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	if err := NewClient(srv.URL).SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if got != "medic-go/"+Version {
+		t.Errorf("default User-Agent = %q, want %q", got, "medic-go/"+Version)
+	}
+
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithUserAgent("my-service/1.0"))
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if got != "my-service/1.0" {
+		t.Errorf("User-Agent = %q, want %q", got, "my-service/1.0")
+	}
+}
+
+func TestWithHeader(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithHeader("X-Tenant-ID", "tenant-a"),
+		WithHeader("X-Correlation-ID", "client-default"),
+		WithHeader("Content-Type", "text/plain"),
+	)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}
+	if err := c.SendHeartbeatContext(context.Background(), h, WithRequestHeader("X-Correlation-ID", "per-call")); err != nil {
+		t.Fatalf("SendHeartbeatContext() unexpected error = %v", err)
+	}
+
+	want := map[string]string{
+		"X-Tenant-Id":      "tenant-a",
+		"X-Correlation-Id": "per-call",
+		"Content-Type":     "application/json",
+	}
+	for key, value := range want {
+		if got.Get(key) != value {
+			t.Errorf("header %s = %q, want %q", key, got.Get(key), value)
+		}
+	}
+}
+
+// correlationKey is the context key TestWithContextHeaderFunc stores its ID under
+type correlationKey struct{}
+
+func TestWithContextHeaderFunc(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithHeader("X-Correlation-ID", "client-default"),
+		WithContextHeaderFunc(func(ctx context.Context) http.Header {
+			id, ok := ctx.Value(correlationKey{}).(string)
+			if !ok {
+				return nil
+			}
+			return http.Header{"X-Correlation-Id": {id}}
+		}),
+	)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts []RequestOption
+		want string
+	}{
+		{name: "testing no context value", ctx: context.Background(), want: "client-default"},
+		{name: "testing context value", ctx: context.WithValue(context.Background(), correlationKey{}, "from-ctx"), want: "from-ctx"},
+		{name: "testing per-call header wins", ctx: context.WithValue(context.Background(), correlationKey{}, "from-ctx"), opts: []RequestOption{WithRequestHeader("X-Correlation-ID", "per-call")}, want: "per-call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.SendHeartbeatContext(tt.ctx, h, tt.opts...); err != nil {
+				t.Fatalf("SendHeartbeatContext() unexpected error = %v", err)
+			}
+			if v := got.Get("X-Correlation-ID"); v != tt.want {
+				t.Errorf("X-Correlation-ID = %q, want %q", v, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthOptions(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		env    string
+		opts   []Option
+		header string
+		want   string
+	}{
+		{name: "testing bearer token", opts: []Option{WithBearerToken("secret")}, header: "Authorization", want: "Bearer secret"},
+		{name: "testing token from env", env: "env-secret", header: "Authorization", want: "Bearer env-secret"},
+		{name: "testing option beats env", env: "env-secret", opts: []Option{WithBearerToken("secret")}, header: "Authorization", want: "Bearer secret"},
+		{name: "testing api key", env: "env-secret", opts: []Option{WithAPIKey("X-API-Key", "key")}, header: "X-API-Key", want: "key"},
+		{name: "testing api key skips env token", env: "env-secret", opts: []Option{WithAPIKey("X-API-Key", "key")}, header: "Authorization", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MEDIC_API_TOKEN", tt.env)
+			c := NewClientWithOptions(append([]Option{WithBaseURL(srv.URL)}, tt.opts...)...)
+			if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}); err != nil {
+				t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+			}
+			if got.Get(tt.header) != tt.want {
+				t.Errorf("header %s = %q, want %q", tt.header, got.Get(tt.header), tt.want)
+			}
+		})
+	}
+}
+
+func TestClientsDoNotShareHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	// Mutating one client's timeout while another sends must not race
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			t.Run("parallel", func(t *testing.T) {
+				t.Parallel()
+				c := NewClient(srv.URL)
+				c.HTTPClient.Timeout = time.Duration(i+1) * time.Second
+				if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+					t.Errorf("SendHeartbeat() unexpected error = %v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestNewClientStrict(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		want    string
+		wantErr bool
+	}{
+		{name: "testing trailing slash", baseURL: "https://medic.example.com/", want: "https://medic.example.com"},
+		{name: "testing path prefix", baseURL: "https://medic.example.com/api//", want: "https://medic.example.com/api"},
+		{name: "testing missing scheme", baseURL: "medic.example.com", wantErr: true},
+		{name: "testing malformed", baseURL: "https://medic example.com:port", wantErr: true},
+		{name: "testing unsupported scheme", baseURL: "ftp://medic.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClientStrict(tt.baseURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClientStrict() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && c.BaseURL != tt.want {
+				t.Errorf("BaseURL = %q, want %q", c.BaseURL, tt.want)
+			}
+		})
+	}
+
+	// NewClient keeps its signature but still normalizes
+	if c := NewClient("https://medic.example.com/"); c.BaseURL != "https://medic.example.com" {
+		t.Errorf("NewClient() BaseURL = %q, want trailing slash stripped", c.BaseURL)
+	}
+}
+
+func TestWithRequestBaseURL(t *testing.T) {
+	hits := map[string]int{}
+	var mu sync.Mutex
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[name]++
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		})
+	}
+	primary := httptest.NewServer(handler("primary"))
+	defer primary.Close()
+	regional := httptest.NewServer(handler("regional"))
+	defer regional.Close()
+
+	c := NewClient(primary.URL)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	if err := c.SendHeartbeatContext(context.Background(), h, WithRequestBaseURL(regional.URL+"/")); err != nil {
+		t.Fatalf("SendHeartbeatContext() unexpected error = %v", err)
+	}
+	if err := c.SendHeartbeatContext(context.Background(), h); err != nil {
+		t.Fatalf("SendHeartbeatContext() unexpected error = %v", err)
+	}
+	if hits["regional"] != 1 || hits["primary"] != 1 {
+		t.Errorf("hits = %v, want one per server", hits)
+	}
+}
+
+func TestWithBasePath(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"success":true,"message":"","results":""}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		basePath string
+		want     []string
+	}{
+		{name: "testing no base path", basePath: "", want: []string{"/heartbeat", "/health"}},
+		{name: "testing base path", basePath: "/api/v2/medic", want: []string{"/api/v2/medic/heartbeat", "/api/v2/medic/health"}},
+		{name: "testing base path slashes", basePath: "api/v2/medic/", want: []string{"/api/v2/medic/heartbeat", "/api/v2/medic/health"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths = nil
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithBasePath(tt.basePath))
+			h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+			if err := c.SendHeartbeat(h); err != nil {
+				t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+			}
+			if err := c.Ping(context.Background()); err != nil {
+				t.Fatalf("Ping() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("paths = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+// doerFunc adapts a function to the Doer interface
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestWithHTTPDoer(t *testing.T) {
+	var got *http.Request
+	d := doerFunc(func(req *http.Request) (*http.Response, error) {
+		got = req
+		return &http.Response{StatusCode: http.StatusCreated, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	c := NewClientWithOptions(WithBaseURL("https://medic.invalid"), WithHTTPDoer(d))
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if got == nil || got.URL.String() != "https://medic.invalid/heartbeat" {
+		t.Errorf("Doer received %v, want a POST to https://medic.invalid/heartbeat", got)
+	}
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithRetry(RetryConfig{MaxAttempts: 1}))
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	tests := []struct {
+		name       string
+		ctxTimeout time.Duration
+		timeout    time.Duration
+	}{
+		{name: "testing option tighter than context", ctxTimeout: time.Minute, timeout: 20 * time.Millisecond},
+		{name: "testing context tighter than option", ctxTimeout: 20 * time.Millisecond, timeout: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+			start := time.Now()
+			err := c.SendHeartbeatContext(ctx, h, WithRequestTimeout(tt.timeout))
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("SendHeartbeatContext() error = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("SendHeartbeatContext() took %v, want the tighter deadline", elapsed)
+			}
+		})
+	}
+}
+
+func TestWithRequestTimeoutLongerThanClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(700 * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	c := NewClientWithOptions(
+		WithBaseURL(srv.URL),
+		WithTimeout(300*time.Millisecond),
+		WithRetry(RetryConfig{MaxAttempts: 1}),
+	)
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	tests := []struct {
+		name    string
+		opts    []RequestOption
+		wantErr bool
+	}{
+		{name: "testing client timeout applies without option", wantErr: true},
+		{name: "testing option extends client timeout", opts: []RequestOption{WithRequestTimeout(3 * time.Second)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.SendHeartbeatContext(context.Background(), h, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendHeartbeatContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithFallbackURLs(t *testing.T) {
+	hits := map[string]int{}
+	var mu sync.Mutex
+	server := func(name string, code int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits[name]++
+			mu.Unlock()
+			w.WriteHeader(code)
+		}))
+	}
+	down := server("down", http.StatusServiceUnavailable)
+	defer down.Close()
+	alsoDown := server("alsoDown", http.StatusBadGateway)
+	defer alsoDown.Close()
+	standby := server("standby", http.StatusCreated)
+	defer standby.Close()
+	notFound := server("notFound", http.StatusNotFound)
+	defer notFound.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	tests := []struct {
+		name      string
+		primary   string
+		fallbacks []string
+		wantErr   bool
+		wantHits  map[string]int
+	}{
+		{name: "testing primary succeeds", primary: standby.URL, fallbacks: []string{down.URL}, wantHits: map[string]int{"standby": 1}},
+		{name: "testing fallback after retries", primary: down.URL, fallbacks: []string{alsoDown.URL, standby.URL}, wantHits: map[string]int{"down": 2, "alsoDown": 2, "standby": 1}},
+		{name: "testing all endpoints fail", primary: down.URL, fallbacks: []string{alsoDown.URL}, wantErr: true, wantHits: map[string]int{"down": 2, "alsoDown": 2}},
+		{name: "testing 4xx does not fall back", primary: notFound.URL, fallbacks: []string{standby.URL}, wantErr: true, wantHits: map[string]int{"notFound": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits = map[string]int{}
+			c := NewClientWithOptions(
+				WithBaseURL(tt.primary),
+				WithFallbackURLs(tt.fallbacks...),
+				WithRetry(RetryConfig{MaxAttempts: 2}),
+				WithClock(newFakeClock()),
+			)
+			err := c.SendHeartbeat(h)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SendHeartbeat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var se *StatusError
+			if tt.wantErr && !errors.As(err, &se) {
+				t.Errorf("SendHeartbeat() error = %v, want a *StatusError in the chain", err)
+			}
+			if !reflect.DeepEqual(hits, tt.wantHits) {
+				t.Errorf("hits = %v, want %v", hits, tt.wantHits)
+			}
+		})
+	}
+}
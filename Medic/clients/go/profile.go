@@ -0,0 +1,91 @@
+package medic
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+)
+
+// WireProfile renames the JSON keys of heartbeats on the wire, for servers
+// that predate the current field names. The zero value is ProfileDefault
+type WireProfile struct {
+	name string
+	// keys maps a default JSON key, such as "heartbeat_name", to the key
+	// the server uses
+	keys map[string]string
+}
+
+var (
+	// ProfileDefault sends and reads the current field names
+	ProfileDefault = WireProfile{}
+	// ProfileLegacy uses the field names of older Medic servers: name,
+	// service, and state
+	ProfileLegacy = NewWireProfile("legacy", map[string]string{
+		"heartbeat_name": "name",
+		"service_name":   "service",
+		"status":         "state",
+	})
+)
+
+// NewWireProfile returns a profile that renames each default JSON key in
+// keys, such as "heartbeat_name", to its value. Other keys keep their names
+func NewWireProfile(name string, keys map[string]string) WireProfile {
+	return WireProfile{name: name, keys: maps.Clone(keys)}
+}
+
+// String returns the profile's name
+func (p WireProfile) String() string {
+	if p.name == "" {
+		return "default"
+	}
+	return p.name
+}
+
+// WithWireProfile encodes heartbeats in JSON requests, and decodes those in
+// lookup responses, with the field names of profile p. A Marshaler and
+// EncodingProtobuf are unaffected
+func WithWireProfile(p WireProfile) Option {
+	return func(c *Client) {
+		c.WireProfile = p
+	}
+}
+
+// toWire renames the keys of a JSON heartbeat object for the server
+func (p WireProfile) toWire(obj []byte) ([]byte, error) {
+	if len(p.keys) == 0 {
+		return obj, nil
+	}
+	return renameKeys(obj, p.keys)
+}
+
+// fromWire renames the keys of a JSON heartbeat object from the server back
+// to the default names
+func (p WireProfile) fromWire(obj []byte) ([]byte, error) {
+	if len(p.keys) == 0 {
+		return obj, nil
+	}
+	names := make(map[string]string, len(p.keys))
+	for def, wire := range p.keys {
+		names[wire] = def
+	}
+	return renameKeys(obj, names)
+}
+
+// renameKeys re-encodes a JSON object with its top-level keys renamed by
+// names. Keys are written in sorted order
+func renameKeys(obj []byte, names map[string]string) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+	renamed := make(map[string]json.RawMessage, len(fields))
+	for k, v := range fields {
+		if name, ok := names[k]; ok {
+			k = name
+		}
+		renamed[k] = v
+	}
+	return json.Marshal(renamed)
+}
@@ -0,0 +1,66 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithWireProfile(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"success":true,"message":"","results":{"name":"staging-fake-heartbeat-hb","service":"fakeservice","state":"UP","metadata":{"region":"eu"}}}`))
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Service: "fakeservice", Status: StatusUp, Metadata: map[string]string{"region": "eu"}}
+	legacy := map[string]any{"name": "staging-fake-heartbeat-hb", "service": "fakeservice", "state": "UP", "metadata": map[string]any{"region": "eu"}}
+	current := map[string]any{"heartbeat_name": "staging-fake-heartbeat-hb", "service_name": "fakeservice", "status": "UP", "metadata": map[string]any{"region": "eu"}}
+	tests := []struct {
+		name    string
+		profile WireProfile
+		send    func(c *Client) error
+		want    any
+	}{
+		{name: "testing default profile", profile: ProfileDefault, send: func(c *Client) error { return c.SendHeartbeat(h) }, want: current},
+		{name: "testing legacy heartbeat", profile: ProfileLegacy, send: func(c *Client) error { return c.SendHeartbeat(h) }, want: legacy},
+		{name: "testing legacy batch", profile: ProfileLegacy, send: func(c *Client) error { return c.SendHeartbeats([]Heartbeat{h}) }, want: []any{legacy}},
+		{name: "testing custom profile", profile: NewWireProfile("custom", map[string]string{"status": "health"}), send: func(c *Client) error { return c.SendHeartbeat(h) },
+			want: map[string]any{"heartbeat_name": "staging-fake-heartbeat-hb", "service_name": "fakeservice", "health": "UP", "metadata": map[string]any{"region": "eu"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithWireProfile(tt.profile))
+			if err := tt.send(c); err != nil {
+				t.Fatalf("send unexpected error = %v", err)
+			}
+			var got any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("body %q is not JSON: %v", body, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("body = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("testing legacy response", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithWireProfile(ProfileLegacy))
+		got, err := c.GetHeartbeat(context.Background(), h.HeartbeatName)
+		if err != nil {
+			t.Fatalf("GetHeartbeat() unexpected error = %v", err)
+		}
+		if !reflect.DeepEqual(*got, h) {
+			t.Errorf("GetHeartbeat() = %+v, want %+v", *got, h)
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultQueueSize is the capacity of a QueuedSender created without WithQueueSize
+const DefaultQueueSize = 256
+
+// ErrQueueClosed is returned by Enqueue once the QueuedSender is closed
+var ErrQueueClosed = errors.New("heartbeat queue is closed")
+
+// QueueOption configures a QueuedSender created with NewQueuedSender
+type QueueOption func(*QueuedSender)
+
+// WithQueueSize sets how many heartbeats the queue holds before it drops the
+// oldest. A non-positive n uses DefaultQueueSize
+func WithQueueSize(n int) QueueOption {
+	return func(q *QueuedSender) {
+		if n > 0 {
+			q.buf = make([]Heartbeat, n)
+		}
+	}
+}
+
+// QueuedSender buffers heartbeats in memory and sends them in order on a
+// background goroutine, so a brief Medic outage delays heartbeats instead of
+// losing them. A heartbeat that fails with a connection error, 429 or 5xx is
+// retried with the client's retry backoff until it is delivered; any other
+// failure drops it. When the queue is full, the oldest heartbeat is dropped
+type QueuedSender struct {
+	client *Client
+
+	// OnError is called with each heartbeat that is dropped because it can't
+	// be sent, and with each failed attempt of one that will be retried
+	OnError func(h Heartbeat, err error)
+
+	mu   sync.Mutex
+	buf  []Heartbeat
+	head int
+	n    int
+	// first numbers the heartbeat at head, counting every one ever dequeued
+	first  uint64
+	closed bool
+	// wake has a value when heartbeats were queued since the loop last looked
+	wake chan struct{}
+
+	dropped atomic.Uint64
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewQueuedSender starts a queue that sends with the given client, or with
+// the default client when c is nil. Close it to flush the queue and stop it.
+// Closing the client instead stops it right away, abandoning queued
+// heartbeats
+func NewQueuedSender(c *Client, opts ...QueueOption) *QueuedSender {
+	if c == nil {
+		c = defaultClient()
+	}
+	q := &QueuedSender{client: c, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.buf == nil {
+		q.buf = make([]Heartbeat, DefaultQueueSize)
+	}
+	ctx, done, err := c.trackAsync(context.Background())
+	if err != nil {
+		// The client is already closed; nothing will ever be sent
+		q.closed = true
+		close(q.done)
+		q.cancel = func() {}
+		return q
+	}
+	ctx, q.cancel = context.WithCancel(ctx)
+	go func() {
+		defer done()
+		q.run(ctx)
+	}()
+	return q
+}
+
+// Enqueue validates h and queues it without waiting for it to be sent. If the
+// queue is full, the oldest queued heartbeat is dropped to make room
+func (q *QueuedSender) Enqueue(h Heartbeat) error {
+	if err := q.client.validate(h); err != nil {
+		return fmt.Errorf("invalid heartbeat: %w", err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	if q.n == len(q.buf) {
+		q.remove()
+		q.dropped.Add(1)
+	}
+	q.buf[(q.head+q.n)%len(q.buf)] = h
+	q.n++
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Len returns the number of heartbeats waiting to be sent
+func (q *QueuedSender) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.n
+}
+
+// Dropped returns how many heartbeats were dropped because the queue was full
+func (q *QueuedSender) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// Close stops accepting heartbeats and sends those still queued, within
+// FinalSendTimeout. See CloseContext
+func (q *QueuedSender) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), FinalSendTimeout)
+	defer cancel()
+	return q.CloseContext(ctx)
+}
+
+// CloseContext stops accepting heartbeats and sends those still queued until
+// ctx is done. It returns an error reporting how many heartbeats were left
+// unsent, or nil once the queue is empty. Closing an already closed queue
+// waits for the first close to finish
+func (q *QueuedSender) CloseContext(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-q.done:
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+	}
+	if n := q.Len(); n > 0 {
+		return fmt.Errorf("heartbeat queue closed with %d heartbeats unsent", n)
+	}
+	return nil
+}
+
+// run sends queued heartbeats until ctx is cancelled or the queue is closed
+// and empty
+func (q *QueuedSender) run(ctx context.Context) {
+	defer close(q.done)
+	defer q.cancel()
+
+	clock := q.client.clock()
+	retry := q.client.Retry.withDefaults()
+	failures := 0
+	for {
+		h, seq, ok, closed := q.peek()
+		if !ok {
+			if closed {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.wake:
+			}
+			continue
+		}
+
+		err := q.client.SendHeartbeatContext(ctx, h)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && queueRetryable(err) {
+			if q.OnError != nil {
+				q.OnError(h, err)
+			}
+			failures++
+			if sleepContext(ctx, clock, retry.backoff(failures)) != nil {
+				return
+			}
+			continue
+		}
+		failures = 0
+		q.pop(seq)
+		if err != nil && q.OnError != nil {
+			q.OnError(h, err)
+		}
+	}
+}
+
+// peek returns the oldest queued heartbeat and its sequence number, if any,
+// and whether the queue is closed
+func (q *QueuedSender) peek() (h Heartbeat, seq uint64, ok, closed bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.n == 0 {
+		return Heartbeat{}, 0, false, q.closed
+	}
+	return q.buf[q.head], q.first, true, q.closed
+}
+
+// pop removes heartbeat seq once it is sent or given up on, unless Enqueue
+// already dropped it to make room
+func (q *QueuedSender) pop(seq uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.n > 0 && q.first == seq {
+		q.remove()
+	}
+}
+
+// remove drops the oldest queued heartbeat; the caller holds q.mu
+func (q *QueuedSender) remove() {
+	q.buf[q.head] = Heartbeat{}
+	q.head = (q.head + 1) % len(q.buf)
+	q.n--
+	q.first++
+}
+
+// queueRetryable reports whether a failed send may succeed if tried again
+func queueRetryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return retryableStatus(se.StatusCode)
+	}
+	var te *TransportError
+	return errors.As(err, &te) || errors.Is(err, ErrCircuitOpen)
+}
@@ -0,0 +1,142 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queueServer records the heartbeat names it receives, answering each with
+// the next status from codes once they run out 201
+type queueServer struct {
+	mu    sync.Mutex
+	names []string
+	codes []int
+	// hold, when set, blocks the first request until it is closed
+	hold     chan struct{}
+	received chan struct{}
+}
+
+func (s *queueServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var h Heartbeat
+	json.NewDecoder(r.Body).Decode(&h)
+	s.mu.Lock()
+	first := len(s.names) == 0
+	code := http.StatusCreated
+	if len(s.codes) > 0 {
+		code, s.codes = s.codes[0], s.codes[1:]
+	}
+	if code < 300 {
+		s.names = append(s.names, h.HeartbeatName)
+	}
+	s.mu.Unlock()
+	if first && s.hold != nil {
+		close(s.received)
+		<-s.hold
+	}
+	w.WriteHeader(code)
+}
+
+func (s *queueServer) delivered() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+func TestQueuedSender(t *testing.T) {
+	beat := func(name string) Heartbeat { return Heartbeat{HeartbeatName: name, Status: StatusUp} }
+	tests := []struct {
+		name        string
+		codes       []int
+		enqueue     []string
+		wantSent    []string
+		wantErrors  int
+		wantDropped uint64
+	}{
+		{name: "testing sends in order", enqueue: []string{"a-hb", "b-hb", "c-hb"}, wantSent: []string{"a-hb", "b-hb", "c-hb"}},
+		{name: "testing retries through an outage", codes: []int{503, 502, 429}, enqueue: []string{"a-hb", "b-hb"}, wantSent: []string{"a-hb", "b-hb"}, wantErrors: 3},
+		{name: "testing client error drops heartbeat", codes: []int{404}, enqueue: []string{"a-hb", "b-hb"}, wantSent: []string{"b-hb"}, wantErrors: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &queueServer{codes: tt.codes}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+			c := NewClientWithOptions(WithBaseURL(ts.URL), WithRetry(RetryConfig{MaxAttempts: 1}), WithClock(newFakeClock()))
+			q := NewQueuedSender(c)
+			var errs int
+			q.OnError = func(Heartbeat, error) { errs++ }
+			for _, name := range tt.enqueue {
+				if err := q.Enqueue(beat(name)); err != nil {
+					t.Fatalf("Enqueue() unexpected error = %v", err)
+				}
+			}
+			if err := q.Close(); err != nil {
+				t.Fatalf("Close() unexpected error = %v", err)
+			}
+			if got := srv.delivered(); !reflect.DeepEqual(got, tt.wantSent) {
+				t.Errorf("delivered = %v, want %v", got, tt.wantSent)
+			}
+			if errs != tt.wantErrors {
+				t.Errorf("OnError called %d times, want %d", errs, tt.wantErrors)
+			}
+			if err := q.Enqueue(beat("late-hb")); !errors.Is(err, ErrQueueClosed) {
+				t.Errorf("Enqueue() after Close error = %v, want ErrQueueClosed", err)
+			}
+		})
+	}
+
+	t.Run("testing full queue drops oldest", func(t *testing.T) {
+		srv := &queueServer{hold: make(chan struct{}), received: make(chan struct{})}
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+		c := NewClientWithOptions(WithBaseURL(ts.URL), WithRetry(RetryConfig{MaxAttempts: 1}))
+		q := NewQueuedSender(c, WithQueueSize(2))
+		q.Enqueue(beat("a-hb"))
+		<-srv.received
+		// a-hb is in flight but still queued, so b-hb fills the queue
+		for _, name := range []string{"b-hb", "c-hb", "d-hb"} {
+			q.Enqueue(beat(name))
+		}
+		if q.Dropped() != 2 || q.Len() != 2 {
+			t.Errorf("Dropped() = %d, Len() = %d, want 2 and 2", q.Dropped(), q.Len())
+		}
+		close(srv.hold)
+		if err := q.Close(); err != nil {
+			t.Fatalf("Close() unexpected error = %v", err)
+		}
+		if got, want := srv.delivered(), []string{"a-hb", "c-hb", "d-hb"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("delivered = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("testing close reports unsent heartbeats", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+		c := NewClientWithOptions(WithBaseURL(ts.URL), WithRetry(RetryConfig{MaxAttempts: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+		q := NewQueuedSender(c)
+		q.Enqueue(beat("a-hb"))
+		q.Enqueue(beat("b-hb"))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := q.CloseContext(ctx); err == nil || q.Len() != 2 {
+			t.Errorf("CloseContext() error = %v with %d queued, want an error and 2", err, q.Len())
+		}
+	})
+
+	t.Run("testing invalid heartbeat", func(t *testing.T) {
+		q := NewQueuedSender(NewClientWithOptions(WithDryRun()))
+		defer q.Close()
+		if err := q.Enqueue(Heartbeat{Status: StatusUp}); err == nil {
+			t.Error("Enqueue() expected a validation error")
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package medic
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter throttles outgoing requests. *rate.Limiter from
+// golang.org/x/time/rate satisfies it
+type RateLimiter interface {
+	// Wait blocks until a request may be made or ctx is done
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimit caps outgoing requests at rps per second, allowing bursts of
+// up to burst requests. Retries count against the limit. A send blocks until
+// the limiter allows it, or fails with the context error once ctx is done.
+// A non-positive rps disables the limit
+func WithRateLimit(rps int, burst int) Option {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.RateLimiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.RateLimiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+}
+
+// WithRateLimiter throttles outgoing requests with a custom limiter, such as
+// one shared between several clients
+func WithRateLimiter(l RateLimiter) Option {
+	return func(c *Client) {
+		c.RateLimiter = l
+	}
+}
+
+// waitRateLimit blocks until the rate limiter allows the request method
+// sends to url
+func (c *Client) waitRateLimit(ctx context.Context, method, url string) error {
+	if c.RateLimiter == nil {
+		return nil
+	}
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return newTransportError(method, url, ctxErr)
+		}
+		// rate.Limiter fails early when the wait would outlast the deadline
+		if _, ok := ctx.Deadline(); ok {
+			return newTransportError(method, url, context.DeadlineExceeded)
+		}
+		return newTransportError(method, url, err)
+	}
+	return nil
+}
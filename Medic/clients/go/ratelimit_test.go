@@ -0,0 +1,46 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithRateLimit(10, 2))
+
+	// The burst goes through immediately
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("burst took %v, want no throttling", elapsed)
+	}
+
+	// The next send waits for a token
+	if err := c.SendHeartbeat(h); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("third send after %v, want it throttled", elapsed)
+	}
+
+	// A context that expires before a token is available fails with its error
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.SendHeartbeatContext(ctx, h); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SendHeartbeatContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
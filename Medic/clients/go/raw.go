@@ -0,0 +1,82 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Do posts a heartbeat in a single attempt and returns the raw HTTP response,
+// for callers that need response headers or the unread body. Unlike
+// SendHeartbeat it does not retry, and a non-2xx status is returned as a
+// response rather than an error. The caller must close the response body,
+// which also releases any WithRequestTimeout
+func (c *Client) Do(ctx context.Context, h Heartbeat, opts ...RequestOption) (httpResp *http.Response, err error) {
+	if c.disabled() {
+		resp := disabledResponse()
+		return &http.Response{Header: resp.Header, Body: io.NopCloser(bytes.NewReader(resp.Body))}, nil
+	}
+	ctx, end := c.startSpan(ctx, "medic.Do")
+	rejected := false
+	defer func() {
+		if httpResp != nil {
+			end(&response{StatusCode: httpResp.StatusCode}, err)
+			c.recordSend(httpResp.StatusCode, err)
+			return
+		}
+		end(nil, err)
+		c.recordOutcome(rejected, 0, err)
+	}()
+
+	if c.configErr != nil {
+		return nil, fmt.Errorf("invalid client configuration: %w", c.configErr)
+	}
+	if err := c.checkClosed(); err != nil {
+		return nil, err
+	}
+	if err := c.validate(h); err != nil {
+		rejected = true
+		return nil, fmt.Errorf("invalid heartbeat: %w", err)
+	}
+	payload, err := c.marshal(h)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode heartbeat: %w", err)
+	}
+
+	rc := newRequestConfig(opts)
+	if err := c.validateBody(ctx, c.baseURL(rc), payload); err != nil {
+		rejected = true
+		return nil, err
+	}
+	ctx, cancel := rc.withTimeout(ctx)
+	defer func() {
+		if httpResp == nil || httpResp.Body == nil {
+			cancel()
+			return
+		}
+		httpResp.Body = &cancelOnClose{ReadCloser: httpResp.Body, cancel: cancel}
+	}()
+	if rc.idempotencyKey == "" {
+		rc.idempotencyKey = newIdempotencyKey()
+	}
+	payload, rc.contentEncoding = c.compress(payload)
+	url := fmt.Sprintf("%s/heartbeat", c.baseURL(rc))
+
+	if c.DryRun {
+		resp, _, err := c.dryRun(ctx, http.MethodPost, url, payload, h.HeartbeatName, rc)
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{Header: resp.Header, Body: io.NopCloser(bytes.NewReader(resp.Body))}, nil
+	}
+	_, err = c.roundTrip(ctx, http.MethodPost, url, payload, h.HeartbeatName, rc, func(req *http.Request, resp *http.Response) (attemptResult, error) {
+		httpResp = resp
+		return attemptResult{transient: retryableStatus(resp.StatusCode)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return httpResp, nil
+}
@@ -0,0 +1,41 @@
+package medic
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Idempotency-Key") == "" {
+			t.Error("Do() sent no Idempotency-Key")
+		}
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"success":false,"message":"slow down"}`))
+	}))
+	defer srv.Close()
+
+	resp, err := NewClient(srv.URL).Do(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+	if err != nil {
+		t.Fatalf("Do() unexpected error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("StatusCode = %d, want 429", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-RateLimit-Remaining"); got != "42" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 42", got)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != `{"success":false,"message":"slow down"}` {
+		t.Errorf("body = %s, want the unread server body", body)
+	}
+
+	if _, err := NewClient(srv.URL).Do(context.Background(), Heartbeat{}); err == nil {
+		t.Error("Do() with no name expected a validation error")
+	}
+}
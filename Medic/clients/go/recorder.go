@@ -0,0 +1,219 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// RecordedExchange is one request attempt and its outcome, as written by
+// WithRecorder
+type RecordedExchange struct {
+	// Time is when the request was sent
+	Time     time.Time         `json:"time"`
+	Request  RecordedRequest   `json:"request"`
+	Response *RecordedResponse `json:"response,omitempty"`
+	// Error is the transport error, when no response was received
+	Error string `json:"error,omitempty"`
+	// Duration is how long the attempt took, including reading the response
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// RecordedRequest is an outgoing request as sent on the wire. Credential
+// headers, such as Authorization, have the value "REDACTED"
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	RecordedBody
+}
+
+// RecordedResponse is the response to a RecordedRequest
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers"`
+	RecordedBody
+}
+
+// RecordedBody holds a body as text when it is valid UTF-8, such as JSON,
+// and in BodyBase64 otherwise, such as gzip or protobuf
+type RecordedBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 []byte `json:"body_base64,omitempty"`
+	// Streamed marks a request body that was streamed, which is not recorded
+	Streamed bool `json:"streamed,omitempty"`
+}
+
+// Bytes returns the recorded body
+func (b RecordedBody) Bytes() []byte {
+	if b.BodyBase64 != nil {
+		return b.BodyBase64
+	}
+	return []byte(b.Body)
+}
+
+// recordBody returns body in its recorded form
+func recordBody(body []byte) RecordedBody {
+	if utf8.Valid(body) {
+		return RecordedBody{Body: string(body)}
+	}
+	return RecordedBody{BodyBase64: body}
+}
+
+// WithRecorder writes every request attempt the client sends, and the
+// response or error it got, to w as one JSON RecordedExchange per line, for
+// forensic replay after an incident. Unlike logging, it records full headers
+// and bodies; credential headers are always redacted. Writes are serialized,
+// and a failing w never fails a request. The recorder sits innermost in the
+// transport, after any WithTransportMiddleware, so it sees what goes on the
+// wire; like other transport options it doesn't apply to a custom Doer, and
+// dry runs send nothing to record
+func WithRecorder(w io.Writer) Option {
+	return func(c *Client) {
+		c.recorder = &recorder{w: w, client: c}
+	}
+}
+
+// recorder writes RecordedExchanges to w
+type recorder struct {
+	client *Client
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// wrap returns a RoundTripper that records every exchange through next
+func (r *recorder) wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		clock := r.client.clock()
+		ex := RecordedExchange{
+			Time: clock.Now(),
+			Request: RecordedRequest{
+				Method:  req.Method,
+				URL:     req.URL.String(),
+				Headers: redactHeaders(req.Header),
+			},
+		}
+		ex.Request.RecordedBody = recordRequestBody(req)
+		defer func() {
+			ex.Duration = clock.Now().Sub(ex.Time)
+			r.write(ex)
+		}()
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			ex.Error = err.Error()
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		ex.Response = &RecordedResponse{StatusCode: resp.StatusCode, Headers: redactHeaders(resp.Header), RecordedBody: recordBody(body)}
+		if err != nil {
+			// Hand the caller the same truncated body and failure
+			ex.Error = err.Error()
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+		}
+		return resp, nil
+	})
+}
+
+// recordRequestBody reads a copy of req's body, which can be read again
+// unless the request streams it
+func recordRequestBody(req *http.Request) RecordedBody {
+	if req.Body == nil || req.Body == http.NoBody {
+		return RecordedBody{}
+	}
+	if req.GetBody == nil {
+		return RecordedBody{Streamed: true}
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return RecordedBody{Streamed: true}
+	}
+	defer rc.Close()
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return RecordedBody{Streamed: true}
+	}
+	return recordBody(body)
+}
+
+// write appends ex to the recording, ignoring write errors
+func (r *recorder) write(ex RecordedExchange) {
+	line, err := json.Marshal(ex)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.w.Write(append(line, '\n'))
+}
+
+// errReader fails every read with err
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// ReadRecording decodes the exchanges WithRecorder wrote to r
+func ReadRecording(r io.Reader) ([]RecordedExchange, error) {
+	var out []RecordedExchange
+	dec := json.NewDecoder(r)
+	for {
+		var ex RecordedExchange
+		err := dec.Decode(&ex)
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return out, fmt.Errorf("recording entry %d: %w", len(out)+1, err)
+		}
+		out = append(out, ex)
+	}
+}
+
+// NewRequest rebuilds the recorded request so it can be replayed. When
+// baseURL is set, it replaces the scheme and host, such as to send the
+// request to a test server. Redacted headers are dropped, so set
+// credentials again before sending
+func (r RecordedRequest) NewRequest(ctx context.Context, baseURL string) (*http.Request, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recorded URL: %w", err)
+	}
+	if baseURL != "" {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL: %w", err)
+		}
+		u.Scheme, u.Host = base.Scheme, base.Host
+	}
+	if r.Streamed {
+		return nil, fmt.Errorf("recorded %s %s streamed its body, which was not recorded", r.Method, r.URL)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, u.String(), bytes.NewReader(r.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.Headers {
+		if len(values) == 1 && values[0] == redacted {
+			continue
+		}
+		req.Header[key] = append([]string(nil), values...)
+	}
+	return req, nil
+}
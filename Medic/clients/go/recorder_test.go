@@ -0,0 +1,119 @@
+package medic
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithRecorder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true,"message":"","results":""}`))
+	}))
+	defer srv.Close()
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	t.Run("testing request and response recorded", func(t *testing.T) {
+		var buf bytes.Buffer
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithBearerToken("secret-token"), WithRecorder(&buf))
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+		if strings.Contains(buf.String(), "secret-token") {
+			t.Errorf("recording contains the bearer token: %s", buf.String())
+		}
+		recs, err := ReadRecording(&buf)
+		if err != nil || len(recs) != 1 {
+			t.Fatalf("ReadRecording() = %d exchanges, %v, want 1", len(recs), err)
+		}
+		req, resp := recs[0].Request, recs[0].Response
+		if req.Method != http.MethodPost || req.URL != srv.URL+"/heartbeat" || req.Headers.Get("Authorization") != redacted {
+			t.Errorf("request = %s %s with Authorization %q, want POST %s/heartbeat redacted", req.Method, req.URL, req.Headers.Get("Authorization"), srv.URL)
+		}
+		if !strings.Contains(req.Body, `"heartbeat_name":"staging-fake-heartbeat-hb"`) {
+			t.Errorf("request body = %q, want the heartbeat", req.Body)
+		}
+		if resp == nil || resp.StatusCode != http.StatusCreated || resp.Body != `{"success":true,"message":"","results":""}` {
+			t.Errorf("response = %+v, want the 201 and its body", resp)
+		}
+	})
+
+	t.Run("testing every attempt recorded", func(t *testing.T) {
+		var attempts atomic.Int64
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer flaky.Close()
+		var buf bytes.Buffer
+		c := NewClientWithOptions(WithBaseURL(flaky.URL), WithClock(newFakeClock()), WithRecorder(&buf))
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+		recs, _ := ReadRecording(&buf)
+		if len(recs) != 2 || recs[0].Response.StatusCode != http.StatusServiceUnavailable || recs[1].Response.StatusCode != http.StatusCreated {
+			t.Errorf("ReadRecording() = %+v, want a 503 then a 201", recs)
+		}
+	})
+
+	t.Run("testing transport error recorded", func(t *testing.T) {
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+		var buf bytes.Buffer
+		c := NewClientWithOptions(WithBaseURL(down.URL), WithRetry(RetryConfig{MaxAttempts: 1}), WithSilentLogging(), WithRecorder(&buf))
+		if err := c.SendHeartbeat(h); err == nil {
+			t.Fatal("SendHeartbeat() expected a connection error")
+		}
+		recs, _ := ReadRecording(&buf)
+		if len(recs) != 1 || recs[0].Response != nil || recs[0].Error == "" {
+			t.Errorf("ReadRecording() = %+v, want one exchange with an error and no response", recs)
+		}
+	})
+
+	t.Run("testing binary body replayed", func(t *testing.T) {
+		var buf bytes.Buffer
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithCompression(1), WithRecorder(&buf))
+		if err := c.SendHeartbeat(h); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+		recs, _ := ReadRecording(&buf)
+		if len(recs) != 1 || recs[0].Request.BodyBase64 == nil {
+			t.Fatalf("ReadRecording() = %+v, want the gzip body in body_base64", recs)
+		}
+
+		got := make(chan string, 1)
+		replay := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("replayed body is not gzip: %v", err)
+				return
+			}
+			body, _ := io.ReadAll(zr)
+			got <- r.URL.Path + " " + r.Header.Get("Authorization") + string(body)
+		}))
+		defer replay.Close()
+		req, err := recs[0].Request.NewRequest(context.Background(), replay.URL)
+		if err != nil {
+			t.Fatalf("NewRequest() unexpected error = %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("replay unexpected error = %v", err)
+		}
+		resp.Body.Close()
+		if body := <-got; !strings.HasPrefix(body, "/heartbeat {") || !strings.Contains(body, "staging-fake-heartbeat-hb") {
+			t.Errorf("replayed request = %q, want the heartbeat posted to /heartbeat", body)
+		}
+	})
+}
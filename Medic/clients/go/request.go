@@ -0,0 +1,156 @@
+package medic
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// RequestOption configures a single call, overriding client-level settings
+type RequestOption func(*requestConfig)
+
+// requestConfig holds the per-call settings collected from RequestOptions
+type requestConfig struct {
+	headers        http.Header
+	strictDelete   bool
+	idempotencyKey string
+	baseURL        string
+	timeout        time.Duration
+	// contentEncoding is set internally when the body was compressed
+	contentEncoding string
+	// fallback is set internally to the fallback base URL being tried, so
+	// the attempt uses that endpoint's circuit breaker
+	fallback string
+}
+
+// newRequestConfig applies opts in order
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	rc := &requestConfig{headers: http.Header{}}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	return rc
+}
+
+// WithRequestHeader sets a header on this call only, overriding any client header of the same name
+func WithRequestHeader(key, value string) RequestOption {
+	return func(rc *requestConfig) {
+		rc.headers.Set(key, value)
+	}
+}
+
+// WithStrictDelete makes DeleteHeartbeat return ErrHeartbeatNotFound when the
+// heartbeat does not exist, instead of treating the delete as already done
+func WithStrictDelete() RequestOption {
+	return func(rc *requestConfig) {
+		rc.strictDelete = true
+	}
+}
+
+// WithIdempotencyKey sets the Idempotency-Key sent with a heartbeat post,
+// replacing the generated one. Use it when idempotency derives from your own data
+func WithIdempotencyKey(key string) RequestOption {
+	return func(rc *requestConfig) {
+		rc.idempotencyKey = key
+	}
+}
+
+// WithRequestBaseURL sends this call to a different Medic server, such as
+// another regional cluster, instead of the client's BaseURL
+func WithRequestBaseURL(baseURL string) RequestOption {
+	return func(rc *requestConfig) {
+		rc.baseURL = normalizeBaseURL(baseURL)
+	}
+}
+
+// WithRequestTimeout bounds this call, including retries, to d instead of
+// HTTPClient.Timeout. The call ends at whichever comes first, the context
+// deadline or the timeout, so a slow cross-region Medic can get a longer
+// budget than everything else, or a local one a shorter budget
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(rc *requestConfig) {
+		rc.timeout = d
+	}
+}
+
+// withTimeout derives a context bounded by the per-call timeout, if any
+func (rc *requestConfig) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if rc.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, rc.timeout)
+}
+
+// cancelOnClose releases a per-call timeout once a raw response body is
+// closed, so the caller can still read the body after Do returns
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// baseURL resolves the base URL for a call, including the client's BasePath.
+// The precedence is the per-call WithRequestBaseURL, then the client's
+// BaseURL, then MEDIC_BASE_URL or DefaultBaseURL, which a zero-value Client
+// relies on. rc may be nil
+func (c *Client) baseURL(rc *requestConfig) string {
+	base := c.BaseURL
+	switch {
+	case rc != nil && rc.baseURL != "":
+		base = rc.baseURL
+	case base == "":
+		base = normalizeBaseURL(GetBaseURL())
+	}
+	return base + normalizeBasePath(c.BasePath)
+}
+
+// newIdempotencyKey returns a random version 4 UUID
+func newIdempotencyKey() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// applyHeaders sets headers on req in precedence order: Accept, auth, client headers,
+// headers from ContextHeaderFunc, then per-call headers, and finally
+// Content-Type, which always wins
+func (c *Client) applyHeaders(req *http.Request, rc *requestConfig, contentType string) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	} else {
+		req.Header.Set("User-Agent", DefaultUserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+	for key, values := range c.Headers {
+		req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	if c.ContextHeaderFunc != nil {
+		for key, values := range c.ContextHeaderFunc(req.Context()) {
+			req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+	if rc.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", rc.idempotencyKey)
+	}
+	for key, values := range rc.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if rc.contentEncoding != "" {
+		req.Header.Set("Content-Encoding", rc.contentEncoding)
+	}
+}
@@ -0,0 +1,227 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeartbeatResponse is the server's reply to a successful heartbeat post
+type HeartbeatResponse struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	Success    bool
+	Message    string
+	// HeartbeatID is the server-assigned heartbeat ID, if returned
+	HeartbeatID string
+	// NextExpectedAt is when the server expects the next heartbeat, if returned
+	NextExpectedAt time.Time
+	// ServerTime is the server's clock when it responded, from the Date
+	// header, with one-second resolution. It is zero if the header is missing
+	ServerTime time.Time
+	// ClockSkew estimates how far the local clock is ahead of ServerTime when
+	// the response arrived; negative means the local clock is behind. It is
+	// zero when ServerTime is
+	ClockSkew time.Duration
+	// Results is the raw results field of the response envelope
+	Results json.RawMessage
+	// RequestID is the server's X-Request-ID for the response, if sent
+	RequestID string
+	// Headers holds the response headers named by WithCapturedHeaders, and
+	// X-Request-ID, that the server sent
+	Headers http.Header
+}
+
+// RequestIDHeader is the response header Medic uses for its request ID. It
+// is always captured into HeartbeatResponse and StatusError
+const RequestIDHeader = "X-Request-ID"
+
+// WithCapturedHeaders copies the named response headers, in addition to
+// X-Request-ID, into HeartbeatResponse.Headers and StatusError.Headers, for
+// quoting server-side identifiers when reporting problems
+func WithCapturedHeaders(names ...string) Option {
+	return func(c *Client) {
+		c.CapturedHeaders = append(c.CapturedHeaders, names...)
+	}
+}
+
+// captureHeaders returns the captured headers present in h, or nil if none are
+func (c *Client) captureHeaders(h http.Header) http.Header {
+	var out http.Header
+	for _, name := range append([]string{RequestIDHeader}, c.CapturedHeaders...) {
+		key := http.CanonicalHeaderKey(name)
+		if values, ok := h[key]; ok {
+			if out == nil {
+				out = http.Header{}
+			}
+			out[key] = append([]string(nil), values...)
+		}
+	}
+	return out
+}
+
+// SendResult describes what a successful heartbeat post did on the server
+type SendResult int
+
+const (
+	// ResultUnknown is returned for a 2xx status with no defined meaning, and on error
+	ResultUnknown SendResult = iota
+	// ResultCreated means the heartbeat was registered for the first time (201)
+	ResultCreated
+	// ResultUpdated means an existing heartbeat was updated (200 or 204)
+	ResultUpdated
+	// ResultAccepted means the heartbeat was queued for processing (202)
+	ResultAccepted
+)
+
+// String returns the result name, e.g. "created"
+func (r SendResult) String() string {
+	switch r {
+	case ResultCreated:
+		return "created"
+	case ResultUpdated:
+		return "updated"
+	case ResultAccepted:
+		return "accepted"
+	default:
+		return "unknown"
+	}
+}
+
+// sendResult maps a 2xx status code to its SendResult
+func sendResult(statusCode int) SendResult {
+	switch statusCode {
+	case http.StatusCreated:
+		return ResultCreated
+	case http.StatusOK, http.StatusNoContent:
+		return ResultUpdated
+	case http.StatusAccepted:
+		return ResultAccepted
+	default:
+		return ResultUnknown
+	}
+}
+
+// heartbeatEnvelope is the standard Medic response envelope
+type heartbeatEnvelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Results json.RawMessage `json:"results"`
+	// NextCursor is set on paginated list responses when more pages remain
+	NextCursor string `json:"next_cursor"`
+}
+
+// heartbeatResults holds the fields Medic may return in the results of a heartbeat post
+type heartbeatResults struct {
+	HeartbeatID    json.RawMessage `json:"heartbeat_id"`
+	NextExpectedAt time.Time       `json:"next_expected_at"`
+}
+
+// SendHeartbeatWithResponse sends a heartbeat post to medic and returns the parsed server response
+func (c *Client) SendHeartbeatWithResponse(ctx context.Context, h Heartbeat, opts ...RequestOption) (*HeartbeatResponse, error) {
+	resp, err := c.sendHeartbeat(ctx, h, opts)
+	if err != nil {
+		return nil, err
+	}
+	hr, err := parseHeartbeatResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := serverTime(resp); ok {
+		hr.ServerTime = t
+		hr.ClockSkew = c.clockSkew(t)
+	}
+	hr.RequestID = resp.Header.Get(RequestIDHeader)
+	hr.Headers = c.captureHeaders(resp.Header)
+	return hr, nil
+}
+
+// SendHeartbeatWithResult sends a heartbeat post to medic and reports whether
+// the server created a new heartbeat, updated an existing one, or accepted it
+// for later processing, so first registrations can be told apart
+func (c *Client) SendHeartbeatWithResult(ctx context.Context, h Heartbeat, opts ...RequestOption) (SendResult, error) {
+	resp, err := c.sendHeartbeat(ctx, h, opts)
+	if err != nil {
+		return ResultUnknown, err
+	}
+	return sendResult(resp.StatusCode), nil
+}
+
+// snippetLen caps the body excerpt kept in a ContentTypeError
+const snippetLen = 64
+
+// checkJSONBody returns a *ContentTypeError when a non-empty response body is
+// not JSON. A body without a JSON Content-Type is accepted if it starts like
+// JSON. Unless strict, only a clearly non-JSON body such as an HTML error
+// page is rejected
+func checkJSONBody(resp *response, strict bool) error {
+	body := bytes.TrimSpace(resp.Body)
+	if len(body) == 0 {
+		return nil
+	}
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+	var ok bool
+	if strict {
+		ok = mediaType != "text/html" && (body[0] == '{' || body[0] == '[')
+	} else {
+		ok = mediaType != "text/html" && body[0] != '<'
+	}
+	if ok {
+		return nil
+	}
+	if len(body) > snippetLen {
+		body = body[:snippetLen]
+	}
+	return &ContentTypeError{StatusCode: resp.StatusCode, ContentType: contentType, Snippet: string(body)}
+}
+
+// parseHeartbeatResponse decodes the response envelope; an empty body yields a bare response
+func parseHeartbeatResponse(resp *response) (*HeartbeatResponse, error) {
+	hr := &HeartbeatResponse{StatusCode: resp.StatusCode}
+	if len(bytes.TrimSpace(resp.Body)) == 0 {
+		return hr, nil
+	}
+	if err := checkJSONBody(resp, true); err != nil {
+		return nil, err
+	}
+
+	var envelope heartbeatEnvelope
+	if err := json.Unmarshal(resp.Body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to decode heartbeat response: %w", err)
+	}
+	hr.Success = envelope.Success
+	hr.Message = envelope.Message
+	hr.Results = envelope.Results
+
+	// Results is an empty string when the server has nothing to add
+	var results heartbeatResults
+	if bytes.HasPrefix(bytes.TrimSpace(envelope.Results), []byte("{")) {
+		if err := json.Unmarshal(envelope.Results, &results); err != nil {
+			return nil, fmt.Errorf("failed to decode heartbeat response: %w", err)
+		}
+		hr.HeartbeatID = rawID(results.HeartbeatID)
+		hr.NextExpectedAt = results.NextExpectedAt
+	}
+	return hr, nil
+}
+
+// rawID renders a JSON string or number ID as a string
+func rawID(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	if s, err := strconv.Unquote(string(raw)); err == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,191 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSendHeartbeatWithResponse(t *testing.T) {
+	next := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   HeartbeatResponse
+	}{
+		{
+			name:   "testing envelope with results",
+			status: http.StatusCreated,
+			body:   `{"success":true,"message":"Heartbeat Posted Successfully.","results":{"heartbeat_id":42,"next_expected_at":"2024-01-01T12:05:00Z"}}`,
+			want:   HeartbeatResponse{StatusCode: 201, Success: true, Message: "Heartbeat Posted Successfully.", HeartbeatID: "42", NextExpectedAt: next},
+		},
+		{
+			name:   "testing empty results",
+			status: http.StatusCreated,
+			body:   `{"success":true,"message":"Heartbeat Posted Successfully.","results":""}`,
+			want:   HeartbeatResponse{StatusCode: 201, Success: true, Message: "Heartbeat Posted Successfully."},
+		},
+		{
+			name:   "testing empty body",
+			status: http.StatusNoContent,
+			want:   HeartbeatResponse{StatusCode: 204},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			got, err := NewClient(srv.URL).SendHeartbeatWithResponse(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+			if err != nil {
+				t.Fatalf("SendHeartbeatWithResponse() unexpected error = %v", err)
+			}
+			if got.StatusCode != tt.want.StatusCode || got.Success != tt.want.Success || got.Message != tt.want.Message ||
+				got.HeartbeatID != tt.want.HeartbeatID || !got.NextExpectedAt.Equal(tt.want.NextExpectedAt) {
+				t.Errorf("SendHeartbeatWithResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusErrorMessage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"success":false,"message":"staging-fake-heartbeat-hb is not listed as a registered heartbeat.","results":""}`))
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL).SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+	var se *StatusError
+	if !errors.As(err, &se) {
+		t.Fatalf("SendHeartbeat() error = %v, want *StatusError", err)
+	}
+	if se.Message != "staging-fake-heartbeat-hb is not listed as a registered heartbeat." {
+		t.Errorf("Message = %q", se.Message)
+	}
+	if want := "unexpected status code 404: staging-fake-heartbeat-hb is not listed as a registered heartbeat."; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestSendHeartbeatWithResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		want   SendResult
+	}{
+		{name: "testing created", status: http.StatusCreated, want: ResultCreated},
+		{name: "testing updated", status: http.StatusOK, want: ResultUpdated},
+		{name: "testing no content", status: http.StatusNoContent, want: ResultUpdated},
+		{name: "testing accepted", status: http.StatusAccepted, want: ResultAccepted},
+		{name: "testing other 2xx", status: http.StatusResetContent, want: ResultUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			got, err := NewClient(srv.URL).SendHeartbeatWithResult(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+			if err != nil {
+				t.Fatalf("SendHeartbeatWithResult() unexpected error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SendHeartbeatWithResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNonJSONResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		contentType  string
+		body         string
+		wantPlain    bool
+		wantResponse bool
+	}{
+		{name: "testing json", contentType: "application/json", body: `{"success":true}`},
+		{name: "testing untyped json", body: `{"success":true}`},
+		{name: "testing html page", contentType: "text/html; charset=utf-8", body: "<html><body>Bad Gateway</body></html>", wantPlain: true, wantResponse: true},
+		{name: "testing plain text", contentType: "text/plain", body: "OK", wantResponse: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accept string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accept = r.Header.Get("Accept")
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			c := NewClient(srv.URL)
+			h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+			var ce *ContentTypeError
+			err := c.SendHeartbeat(h)
+			if got := errors.As(err, &ce); got != tt.wantPlain {
+				t.Errorf("SendHeartbeat() error = %v, want ContentTypeError %v", err, tt.wantPlain)
+			}
+			if accept != "application/json" {
+				t.Errorf("Accept = %q, want application/json", accept)
+			}
+			_, err = c.SendHeartbeatWithResponse(context.Background(), h)
+			if got := errors.As(err, &ce); got != tt.wantResponse {
+				t.Errorf("SendHeartbeatWithResponse() error = %v, want ContentTypeError %v", err, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestResponseRequestID(t *testing.T) {
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "abc123")
+		w.Header().Set("X-Medic-Node", "node-2")
+		w.Header().Set("X-Other", "ignored")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithCapturedHeaders("x-medic-node"), WithRetry(RetryConfig{MaxAttempts: 1}))
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	want := http.Header{"X-Request-Id": {"abc123"}, "X-Medic-Node": {"node-2"}}
+
+	t.Run("testing success", func(t *testing.T) {
+		status = http.StatusCreated
+		hr, err := c.SendHeartbeatWithResponse(context.Background(), h)
+		if err != nil {
+			t.Fatalf("SendHeartbeatWithResponse() unexpected error = %v", err)
+		}
+		if hr.RequestID != "abc123" || !reflect.DeepEqual(hr.Headers, want) {
+			t.Errorf("RequestID, Headers = %q, %v, want abc123, %v", hr.RequestID, hr.Headers, want)
+		}
+	})
+
+	t.Run("testing status error", func(t *testing.T) {
+		status = http.StatusBadRequest
+		err := c.SendHeartbeat(h)
+		var se *StatusError
+		if !errors.As(err, &se) {
+			t.Fatalf("SendHeartbeat() error = %v, want *StatusError", err)
+		}
+		if se.RequestID != "abc123" || !reflect.DeepEqual(se.Headers, want) {
+			t.Errorf("RequestID, Headers = %q, %v, want abc123, %v", se.RequestID, se.Headers, want)
+		}
+		if err.Error() != "unexpected status code 400 (request ID abc123)" {
+			t.Errorf("Error() = %q, want the request ID", err.Error())
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package medic
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryConfig controls how transient heartbeat failures are retried
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Set to 1 to disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt; it doubles each retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts
+	MaxDelay time.Duration
+}
+
+// DefaultRetryConfig is used for any RetryConfig field left at its zero value
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// withDefaults fills zero fields from DefaultRetryConfig
+func (r RetryConfig) withDefaults() RetryConfig {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = DefaultRetryConfig.MaxAttempts
+	}
+	if r.BaseDelay <= 0 {
+		r.BaseDelay = DefaultRetryConfig.BaseDelay
+	}
+	if r.MaxDelay <= 0 {
+		r.MaxDelay = DefaultRetryConfig.MaxDelay
+	}
+	return r
+}
+
+// backoff returns the jittered delay to wait before the given retry (1-based)
+func (r RetryConfig) backoff(retry int) time.Duration {
+	delay := r.BaseDelay
+	for i := 1; i < retry && delay < r.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	// Jitter uniformly across the upper half of the window so retries from
+	// many clients spread out without collapsing to zero
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// attemptResult describes the outcome of a single request attempt
+type attemptResult struct {
+	// retryable is the retry policy's decision
+	retryable bool
+	// transient marks a connection error, 429, or 5xx, which the circuit
+	// breaker counts as a failure whatever the retry policy decides
+	transient  bool
+	retryAfter time.Duration
+}
+
+// retryableStatus reports whether a response status is a transient failure
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// RetryPolicy decides whether a failed attempt is retried. Exactly one of
+// resp and err is non-nil: resp is a non-2xx response whose body has already
+// been read, and err is a connection error. Cancellation of the call's
+// context is never retried and does not reach the policy
+type RetryPolicy func(req *http.Request, resp *http.Response, err error) bool
+
+// DefaultRetryPolicy retries transient failures (connection errors, 429, and
+// 5xx) only where repeating the request is safe: GET, HEAD, OPTIONS, and PUT
+// always; POST and PATCH only when an Idempotency-Key header is set, which
+// every heartbeat post has unless it is removed; and DELETE never on a 4xx,
+// including 429
+func DefaultRetryPolicy(req *http.Request, resp *http.Response, err error) bool {
+	if resp != nil && !retryableStatus(resp.StatusCode) {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut:
+		return true
+	case http.MethodPost, http.MethodPatch:
+		return req.Header.Get("Idempotency-Key") != ""
+	case http.MethodDelete:
+		return resp == nil || resp.StatusCode >= http.StatusInternalServerError
+	default:
+		return false
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy, for precise control over
+// which methods and statuses are retried. RetryConfig still bounds the
+// number of attempts and the backoff
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = p
+	}
+}
+
+// shouldRetry applies the client's retry policy, or DefaultRetryPolicy
+func (c *Client) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy(req, resp, err)
+	}
+	return DefaultRetryPolicy(req, resp, err)
+}
+
+// parseRetryAfter parses a Retry-After header in either delta-seconds or
+// HTTP-date form, returning the wait relative to now
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits on clock for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,249 @@
+package medic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendHeartbeatRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      string
+		wantAttempts int32
+	}{
+		{
+			name:         "testing recovery after 503",
+			statuses:     []int{503, 503, 201},
+			wantAttempts: 3,
+		},
+		{
+			name:         "testing exhausted retries",
+			statuses:     []int{500, 502, 503},
+			wantErr:      "heartbeat failed after 3 attempts: unexpected status code 503",
+			wantAttempts: 3,
+		},
+		{
+			name:         "testing 4xx is not retried",
+			statuses:     []int{400, 201},
+			wantErr:      "unexpected status code 400",
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL)
+			c.Retry = RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+			err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"})
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("SendHeartbeat() unexpected error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("SendHeartbeat() error = %v, want %q", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantAttempts {
+				t.Errorf("SendHeartbeat() attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	r := RetryConfig{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for retry, ceiling := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 300 * time.Millisecond} {
+		d := r.backoff(retry)
+		if d < ceiling/2 || d > ceiling {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", retry, d, ceiling/2, ceiling)
+		}
+	}
+}
+
+func TestSendHeartbeatRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	clock := newFakeClock()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock))
+	c.Retry = RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: "UP"}); err != nil {
+		t.Errorf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 1 || sleeps[0] != 30*time.Second {
+		t.Errorf("slept %v, want [30s] from Retry-After", sleeps)
+	}
+}
+
+func TestSendHeartbeatBackoffUsesClock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	// Hour-long backoffs finish instantly on the fake clock
+	clock := newFakeClock()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock),
+		WithRetry(RetryConfig{MaxAttempts: 4, BaseDelay: time.Hour, MaxDelay: 2 * time.Hour}))
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb"}); err == nil {
+		t.Fatal("SendHeartbeat() expected an error")
+	}
+	sleeps := clock.Sleeps()
+	if len(sleeps) != 3 {
+		t.Fatalf("slept %d times, want 3", len(sleeps))
+	}
+	for i, ceiling := range []time.Duration{time.Hour, 2 * time.Hour, 2 * time.Hour} {
+		if sleeps[i] < ceiling/2 || sleeps[i] > ceiling {
+			t.Errorf("sleep %d = %v, want within [%v, %v]", i, sleeps[i], ceiling/2, ceiling)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "testing delta seconds", header: "5", want: 5 * time.Second, wantOK: true},
+		{name: "testing http date", header: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second, wantOK: true},
+		{name: "testing past http date", header: now.Add(-time.Minute).Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "testing missing header", header: "", wantOK: false},
+		{name: "testing garbage", header: "soon", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.header, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKeyReusedAcrossRetries(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.Retry = RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	if err := c.SendHeartbeat(h); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if err := c.SendHeartbeatContext(context.Background(), h, WithIdempotencyKey("business-key")); err != nil {
+		t.Fatalf("SendHeartbeatContext() unexpected error = %v", err)
+	}
+
+	if len(keys) != 4 {
+		t.Fatalf("server saw %d requests, want 4", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("retry keys = %q, %q, want the same generated key", keys[0], keys[1])
+	}
+	if keys[2] != "business-key" || keys[3] != "business-key" {
+		t.Errorf("custom keys = %q, %q, want business-key", keys[2], keys[3])
+	}
+	if keys[0] == keys[2] {
+		t.Errorf("separate sends reused key %q", keys[0])
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("X-Fail") == "429" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		call      func(c *Client) error
+		wantCalls int32
+	}{
+		{
+			name:      "testing GET retried",
+			call:      func(c *Client) error { _, err := c.GetHeartbeat(ctx, "staging-fake-heartbeat-hb"); return err },
+			wantCalls: 3,
+		},
+		{
+			name:      "testing POST with idempotency key retried",
+			call:      func(c *Client) error { return c.SendHeartbeatContext(ctx, h) },
+			wantCalls: 3,
+		},
+		{
+			name: "testing POST without idempotency key not retried",
+			call: func(c *Client) error {
+				return c.SendHeartbeatContext(ctx, h, WithRequestHeader("Idempotency-Key", ""))
+			},
+			wantCalls: 1,
+		},
+		{
+			name:      "testing DELETE retried on 5xx",
+			call:      func(c *Client) error { return c.DeleteHeartbeat(ctx, "staging-fake-heartbeat-hb") },
+			wantCalls: 3,
+		},
+		{
+			name: "testing DELETE not retried on 429",
+			call: func(c *Client) error {
+				return c.DeleteHeartbeat(ctx, "staging-fake-heartbeat-hb", WithRequestHeader("X-Fail", "429"))
+			},
+			wantCalls: 1,
+		},
+		{
+			name:      "testing custom policy",
+			policy:    func(*http.Request, *http.Response, error) bool { return false },
+			call:      func(c *Client) error { _, err := c.GetHeartbeat(ctx, "staging-fake-heartbeat-hb"); return err },
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&calls, 0)
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(newFakeClock()), WithRetryPolicy(tt.policy))
+			if err := tt.call(c); err == nil {
+				t.Fatal("expected an error from the failing server")
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("server called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
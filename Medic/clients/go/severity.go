@@ -0,0 +1,50 @@
+package medic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is how urgently Medic should alert when a heartbeat goes down or
+// stale, so alerts can be routed by the sender's intent
+type Severity string
+
+// Allowed heartbeat severities
+const (
+	SeverityCritical Severity = "CRITICAL"
+	SeverityWarning  Severity = "WARNING"
+	SeverityInfo     Severity = "INFO"
+)
+
+// severities lists every allowed Severity from most to least urgent
+var severities = []Severity{SeverityCritical, SeverityWarning, SeverityInfo}
+
+// ParseSeverity converts s to a Severity, ignoring case and surrounding
+// whitespace
+func ParseSeverity(s string) (Severity, error) {
+	for _, severity := range severities {
+		if strings.EqualFold(strings.TrimSpace(s), string(severity)) {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("invalid severity %q: must be one of %s", s, strings.Join(Severity("").allowed(), ", "))
+}
+
+// valid reports whether s is one of the allowed severities
+func (s Severity) valid() bool {
+	for _, severity := range severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed returns the allowed severities as strings
+func (Severity) allowed() []string {
+	out := make([]string, len(severities))
+	for i, severity := range severities {
+		out[i] = string(severity)
+	}
+	return out
+}
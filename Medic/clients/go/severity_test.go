@@ -0,0 +1,53 @@
+package medic
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Severity
+		wantErr bool
+	}{
+		{in: "CRITICAL", want: SeverityCritical},
+		{in: "warning", want: SeverityWarning},
+		{in: " Info ", want: SeverityInfo},
+		{in: "urgent", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSeverity(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSeverity(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSeverity(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity Severity
+		want     string
+	}{
+		{name: "testing severity sent", severity: SeverityCritical, want: `{"heartbeat_name":"staging-fake-heartbeat-hb","status":"DOWN","severity":"CRITICAL"}`},
+		{name: "testing unset severity omitted", want: `{"heartbeat_name":"staging-fake-heartbeat-hb","status":"DOWN"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusDown, Severity: tt.severity})
+			if err != nil {
+				t.Fatalf("json.Marshal() unexpected error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
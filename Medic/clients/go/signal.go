@@ -0,0 +1,51 @@
+package medic
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownSignals are the signals MonitorUntilSignal waits for when none are given
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// MonitorUntilSignal runs a Monitor for h with the default client until one
+// of signals arrives, then sends a final StatusDown heartbeat and returns
+func MonitorUntilSignal(h Heartbeat, interval time.Duration, signals ...os.Signal) error {
+	return defaultClient().MonitorUntilSignalContext(context.Background(), h, interval, signals...)
+}
+
+// MonitorUntilSignalContext is MonitorUntilSignal that also returns, after
+// the final heartbeat, when ctx is cancelled
+func MonitorUntilSignalContext(ctx context.Context, h Heartbeat, interval time.Duration, signals ...os.Signal) error {
+	return defaultClient().MonitorUntilSignalContext(ctx, h, interval, signals...)
+}
+
+// MonitorUntilSignal sends h every interval until one of signals arrives,
+// then sends a final StatusDown heartbeat and returns its error. With no
+// signals it waits for SIGINT and SIGTERM. It blocks, so call it last in main
+// or from its own goroutine
+func (c *Client) MonitorUntilSignal(h Heartbeat, interval time.Duration, signals ...os.Signal) error {
+	return c.MonitorUntilSignalContext(context.Background(), h, interval, signals...)
+}
+
+// MonitorUntilSignalContext is MonitorUntilSignal that also stops when ctx is
+// cancelled, for callers that manage shutdown themselves. The signals are
+// only watched while it runs and other handlers for them keep receiving them
+func (c *Client) MonitorUntilSignalContext(ctx context.Context, h Heartbeat, interval time.Duration, signals ...os.Signal) error {
+	if len(signals) == 0 {
+		signals = shutdownSignals
+	}
+	stopCtx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+
+	m := NewMonitor(c)
+	// The monitor must outlive stopCtx so StopWithStatus has a loop to stop
+	if err := m.Start(context.WithoutCancel(ctx), h, interval); err != nil {
+		return err
+	}
+	<-stopCtx.Done()
+	return m.StopWithStatus(StatusDown)
+}
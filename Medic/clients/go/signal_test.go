@@ -0,0 +1,65 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMonitorUntilSignal(t *testing.T) {
+	tests := []struct {
+		name   string
+		signal bool
+	}{
+		{name: "testing signal", signal: true},
+		{name: "testing context cancel"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan Status, 10)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var h Heartbeat
+				json.NewDecoder(r.Body).Decode(&h)
+				received <- h.Status
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer srv.Close()
+			c := NewClient(srv.URL)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+			result := make(chan error, 1)
+			go func() { result <- c.MonitorUntilSignalContext(ctx, h, time.Hour, os.Interrupt) }()
+
+			// The first beat means the signal handler is installed
+			if got := <-received; got != StatusUp {
+				t.Fatalf("first status = %q, want %q", got, StatusUp)
+			}
+			if tt.signal {
+				p, _ := os.FindProcess(os.Getpid())
+				if err := p.Signal(os.Interrupt); err != nil {
+					t.Skipf("cannot signal own process: %v", err)
+				}
+			} else {
+				cancel()
+			}
+
+			select {
+			case err := <-result:
+				if err != nil {
+					t.Fatalf("MonitorUntilSignalContext() unexpected error = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("MonitorUntilSignalContext() did not return")
+			}
+			if got := <-received; got != StatusDown {
+				t.Errorf("final status = %q, want %q", got, StatusDown)
+			}
+		})
+	}
+}
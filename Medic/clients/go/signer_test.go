@@ -0,0 +1,64 @@
+package medic
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestWithHMACSigner(t *testing.T) {
+	secret := []byte("fake-signing-secret")
+	clock := newFakeClock()
+	type signed struct {
+		keyID, timestamp string
+		valid            bool
+	}
+	got := make(chan signed, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get(SignatureTimestampHeader)
+		got <- signed{
+			keyID:     r.Header.Get(SignatureKeyIDHeader),
+			timestamp: ts,
+			valid:     r.Header.Get(SignatureHeader) == Signature(secret, ts, body),
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	tests := []struct {
+		name string
+		opts []Option
+		send func(c *Client) error
+	}{
+		{name: "testing heartbeat body", send: func(c *Client) error { return c.SendHeartbeat(h) }},
+		{name: "testing compressed body", opts: []Option{WithCompression(1)}, send: func(c *Client) error { return c.SendHeartbeat(h) }},
+		{name: "testing request without body", send: func(c *Client) error { return c.Ping(context.Background()) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithBaseURL(srv.URL), WithClock(clock), WithHMACSigner("fake-key", secret)}, tt.opts...)
+			if err := tt.send(NewClientWithOptions(opts...)); err != nil {
+				t.Fatalf("send unexpected error = %v", err)
+			}
+			s := <-got
+			if !s.valid {
+				t.Error("X-Signature does not match the body and timestamp")
+			}
+			if s.keyID != "fake-key" || s.timestamp != strconv.FormatInt(clock.Now().Unix(), 10) {
+				t.Errorf("key ID = %q, timestamp = %q, want fake-key and the clock's Unix time", s.keyID, s.timestamp)
+			}
+		})
+	}
+
+	t.Run("testing empty secret", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithHMACSigner("fake-key", nil))
+		if err := c.SendHeartbeat(h); err == nil {
+			t.Error("SendHeartbeat() expected a configuration error")
+		}
+	})
+}
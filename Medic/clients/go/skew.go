@@ -0,0 +1,52 @@
+package medic
+
+import (
+	"net/http"
+	"time"
+)
+
+// WithClockSkewWarning logs a warning whenever a heartbeat response shows
+// the local clock differs from the server's by more than threshold. The
+// Date header only has one-second resolution, so thresholds below a few
+// seconds produce false alarms
+func WithClockSkewWarning(threshold time.Duration) Option {
+	return func(c *Client) {
+		c.ClockSkewThreshold = threshold
+	}
+}
+
+// serverTime returns the server's clock reading from the Date header
+func serverTime(resp *response) (time.Time, bool) {
+	if resp == nil || resp.Header == nil {
+		return time.Time{}, false
+	}
+	date := resp.Header.Get("Date")
+	if date == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// clockSkew estimates how far the local clock is ahead of serverTime; a
+// negative skew means the local clock is behind
+func (c *Client) clockSkew(serverTime time.Time) time.Duration {
+	return c.clock().Now().Sub(serverTime)
+}
+
+// checkClockSkew warns when resp shows skew beyond ClockSkewThreshold
+func (c *Client) checkClockSkew(label string, resp *response) {
+	if c.ClockSkewThreshold <= 0 {
+		return
+	}
+	t, ok := serverTime(resp)
+	if !ok {
+		return
+	}
+	if skew := c.clockSkew(t); skew > c.ClockSkewThreshold || -skew > c.ClockSkewThreshold {
+		c.logClockSkew(label, skew)
+	}
+}
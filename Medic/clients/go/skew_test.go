@@ -0,0 +1,59 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	serverNow := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverNow.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		offset   time.Duration
+		wantWarn bool
+	}{
+		{name: "testing clocks agree", offset: 0},
+		{name: "testing local clock ahead", offset: 3 * time.Minute, wantWarn: true},
+		{name: "testing local clock behind", offset: -3 * time.Minute, wantWarn: true},
+		{name: "testing skew within threshold", offset: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			clock.now = serverNow.Add(tt.offset)
+			var logs bytes.Buffer
+			c := NewClientWithOptions(
+				WithBaseURL(srv.URL),
+				WithClock(clock),
+				WithClockSkewWarning(time.Minute),
+				WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+			)
+			h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+			got, err := c.SendHeartbeatWithResponse(context.Background(), h)
+			if err != nil {
+				t.Fatalf("SendHeartbeatWithResponse() unexpected error = %v", err)
+			}
+			if !got.ServerTime.Equal(serverNow) {
+				t.Errorf("ServerTime = %v, want %v", got.ServerTime, serverNow)
+			}
+			if got.ClockSkew != tt.offset {
+				t.Errorf("ClockSkew = %v, want %v", got.ClockSkew, tt.offset)
+			}
+			if warned := strings.Contains(logs.String(), "Local clock differs"); warned != tt.wantWarn {
+				t.Errorf("warned = %v, want %v; logs: %s", warned, tt.wantWarn, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package medic
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of a client's heartbeat delivery counters, suitable
+// for serving on a metrics or status endpoint. A send is one heartbeat post,
+// single or batch, counted once however many attempts it took
+type Stats struct {
+	// Sends is the number of heartbeat posts made
+	Sends uint64 `json:"sends"`
+	// Successes counts posts that were delivered
+	Successes uint64 `json:"successes"`
+	// ClientErrors counts posts that ended with a 4xx status
+	ClientErrors uint64 `json:"client_errors"`
+	// ServerErrors counts posts that ended with a 5xx status
+	ServerErrors uint64 `json:"server_errors"`
+	// OtherFailures counts posts that failed without a status, such as on
+	// connection or timeout errors
+	OtherFailures uint64 `json:"other_failures"`
+	// Rejected counts heartbeats, single or batch, that failed local
+	// validation, including a BodyValidator, so no post was made. They are
+	// not counted in Sends
+	Rejected uint64 `json:"rejected"`
+	// LastError is the message of the most recent failure, if any
+	LastError string `json:"last_error,omitempty"`
+	// LastErrorTime is when the most recent failure happened
+	LastErrorTime time.Time `json:"last_error_time"`
+	// LastSuccess is when the most recent post was delivered
+	LastSuccess time.Time `json:"last_success"`
+
+	// Connections counts the connections taken for request attempts, of
+	// any kind, whether newly dialed or reused from the pool
+	Connections uint64 `json:"connections"`
+	// ReusedConnections counts the Connections that were reused
+	ReusedConnections uint64 `json:"reused_connections"`
+	// ConnectionReuseRatio is ReusedConnections / Connections, or 0 before
+	// the first request. A ratio near 0 under steady traffic means
+	// keep-alive isn't working, so each request pays for a new connection
+	ConnectionReuseRatio float64 `json:"connection_reuse_ratio"`
+}
+
+// sendStats holds a client's counters; it is safe for concurrent use
+type sendStats struct {
+	sends, successes, clientErrors, serverErrors, otherFailures atomic.Uint64
+	rejected                                                    atomic.Uint64
+	lastSuccess                                                 atomic.Int64
+	connections, reusedConns                                    atomic.Uint64
+
+	mu            sync.Mutex
+	lastError     string
+	lastErrorTime time.Time
+}
+
+// Stats returns a snapshot of the client's delivery counters. It is cheap and
+// safe to call from any goroutine
+func (c *Client) Stats() Stats {
+	s := Stats{
+		Sends:         c.stats.sends.Load(),
+		Successes:     c.stats.successes.Load(),
+		ClientErrors:  c.stats.clientErrors.Load(),
+		ServerErrors:  c.stats.serverErrors.Load(),
+		OtherFailures: c.stats.otherFailures.Load(),
+		Rejected:      c.stats.rejected.Load(),
+
+		ReusedConnections: c.stats.reusedConns.Load(),
+		Connections:       c.stats.connections.Load(),
+	}
+	if s.Connections > 0 {
+		s.ConnectionReuseRatio = float64(s.ReusedConnections) / float64(s.Connections)
+	}
+	if ns := c.stats.lastSuccess.Load(); ns != 0 {
+		s.LastSuccess = time.Unix(0, ns)
+	}
+	c.stats.mu.Lock()
+	s.LastError, s.LastErrorTime = c.stats.lastError, c.stats.lastErrorTime
+	c.stats.mu.Unlock()
+	return s
+}
+
+// recordOutcome counts a heartbeat that failed local validation when
+// rejected, and otherwise a post, as recordSend does
+func (c *Client) recordOutcome(rejected bool, statusCode int, err error) {
+	if rejected {
+		c.stats.rejected.Add(1)
+		return
+	}
+	c.recordSend(statusCode, err)
+}
+
+// recordSend counts one heartbeat post that ended with statusCode, or 0 if
+// there was no response, and err. Pass statusCode(resp, err) so a
+// *StatusError is classed by its code
+func (c *Client) recordSend(statusCode int, err error) {
+	now := c.clock().Now()
+	c.stats.sends.Add(1)
+	switch {
+	case err == nil && (statusCode == 0 || c.isSuccess(statusCode)):
+		c.stats.successes.Add(1)
+		c.stats.lastSuccess.Store(now.UnixNano())
+		return
+	case statusCode >= 400 && statusCode < 500:
+		c.stats.clientErrors.Add(1)
+	case statusCode >= 500:
+		c.stats.serverErrors.Add(1)
+	default:
+		c.stats.otherFailures.Add(1)
+	}
+
+	msg := "unexpected status code"
+	if err != nil {
+		msg = err.Error()
+	}
+	c.stats.mu.Lock()
+	c.stats.lastError, c.stats.lastErrorTime = msg, now
+	c.stats.mu.Unlock()
+}
+
+// recordConn counts a connection taken for a request attempt
+func (s *sendStats) recordConn(reused bool) {
+	if reused {
+		s.reusedConns.Add(1)
+	}
+	s.connections.Add(1)
+}
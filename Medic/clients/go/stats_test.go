@@ -0,0 +1,125 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var h Heartbeat
+		if err := json.NewDecoder(r.Body).Decode(&h); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch h.HeartbeatName {
+		case "not-found-hb":
+			w.WriteHeader(http.StatusNotFound)
+		case "server-error-hb":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	clock := newFakeClock()
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock), WithRetry(RetryConfig{MaxAttempts: 1}))
+	if got := c.Stats(); got != (Stats{}) {
+		t.Errorf("Stats() before any send = %+v, want zero", got)
+	}
+
+	ctx := context.Background()
+	names := []string{"staging-fake-heartbeat-hb", "staging-fake-heartbeat-hb", "not-found-hb", "server-error-hb", "Not Valid"}
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			c.SendHeartbeatContext(ctx, Heartbeat{HeartbeatName: name, Status: StatusUp})
+			c.Stats()
+		}(name)
+	}
+	wg.Wait()
+
+	got := c.Stats()
+	want := Stats{Sends: 4, Successes: 2, ClientErrors: 1, ServerErrors: 1, Rejected: 1}
+	if got.Sends != want.Sends || got.Successes != want.Successes || got.ClientErrors != want.ClientErrors ||
+		got.ServerErrors != want.ServerErrors || got.OtherFailures != want.OtherFailures || got.Rejected != want.Rejected {
+		t.Errorf("Stats() = %+v, want counts %+v", got, want)
+	}
+	if !got.LastSuccess.Equal(clock.Now()) || !got.LastErrorTime.Equal(clock.Now()) || got.LastError == "" {
+		t.Errorf("Stats() = %+v, want last success, error and error time set", got)
+	}
+}
+
+func TestStatsRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	valid := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	invalid := Heartbeat{HeartbeatName: "Not Valid", Status: StatusUp}
+
+	tests := []struct {
+		name string
+		send func(c *Client) error
+	}{
+		{name: "testing single", send: func(c *Client) error { return c.SendHeartbeat(invalid) }},
+		{name: "testing batch", send: func(c *Client) error { return c.SendHeartbeats([]Heartbeat{valid, invalid}) }},
+		{name: "testing raw", send: func(c *Client) error {
+			_, err := c.Do(context.Background(), invalid)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientWithOptions(WithBaseURL(srv.URL))
+			if err := tt.send(c); err == nil {
+				t.Fatal("send unexpected success")
+			}
+			if got := c.Stats(); got.Sends != 0 || got.OtherFailures != 0 || got.Rejected != 1 || got.LastError != "" {
+				t.Errorf("Stats() = %+v, want only Rejected counted", got)
+			}
+		})
+	}
+}
+
+func TestStatsConnectionReuse(t *testing.T) {
+	tests := []struct {
+		name       string
+		close      bool
+		wantReused uint64
+		wantRatio  float64
+	}{
+		{name: "testing keep-alive reuses connections", wantReused: 3, wantRatio: 0.75},
+		{name: "testing server closing connections", close: true, wantReused: 0, wantRatio: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.close {
+					w.Header().Set("Connection", "close")
+				}
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer srv.Close()
+			c := NewClientWithOptions(WithBaseURL(srv.URL))
+
+			for range 4 {
+				if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+					t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+				}
+			}
+			got := c.Stats()
+			if got.Connections != 4 || got.ReusedConnections != tt.wantReused || got.ConnectionReuseRatio != tt.wantRatio {
+				t.Errorf("Stats() connections = %d, reused = %d, ratio = %v, want 4, %d, %v",
+					got.Connections, got.ReusedConnections, got.ConnectionReuseRatio, tt.wantReused, tt.wantRatio)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package medic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is the reported state of a heartbeat
+type Status string
+
+// Allowed heartbeat statuses
+const (
+	StatusUp       Status = "UP"
+	StatusDown     Status = "DOWN"
+	StatusDegraded Status = "DEGRADED"
+)
+
+// statuses lists every allowed Status in display order
+var statuses = []Status{StatusUp, StatusDown, StatusDegraded}
+
+// ParseStatus converts s to a Status, ignoring case and surrounding whitespace
+func ParseStatus(s string) (Status, error) {
+	for _, status := range statuses {
+		if strings.EqualFold(strings.TrimSpace(s), string(status)) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("invalid status %q: must be one of %s", s, strings.Join(Status("").allowed(), ", "))
+}
+
+// valid reports whether s is one of the allowed statuses
+func (s Status) valid() bool {
+	for _, status := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed returns the allowed statuses as strings
+func (Status) allowed() []string {
+	out := make([]string, len(statuses))
+	for i, status := range statuses {
+		out[i] = string(status)
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package medic
+
+import "testing"
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Status
+		wantErr bool
+	}{
+		{in: "UP", want: StatusUp},
+		{in: "up", want: StatusUp},
+		{in: " Down ", want: StatusDown},
+		{in: "degraded", want: StatusDegraded},
+		{in: "sideways", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseStatus(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStatus(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStatus(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// streamBuffer is the capacity of the channels StreamHeartbeats returns
+const streamBuffer = 64
+
+// errStreamEnded is reported when the server completes a heartbeat stream
+// the client was still writing to
+var errStreamEnded = errors.New("heartbeat stream closed by server")
+
+// StreamHeartbeats opens one long-lived POST to the /heartbeat/stream
+// endpoint and writes each heartbeat sent on the returned channel to it as a
+// line of JSON (application/x-ndjson), for services that beat several times a
+// second. Invalid heartbeats and lost connections are reported on the error
+// channel without stopping the stream; errors are dropped while it is full. A
+// lost connection is reopened with the client's retry backoff and the
+// heartbeat being written is sent again.
+//
+// The stream ends when ctx is cancelled, the heartbeat channel is closed, or
+// the client is closed.
+// Heartbeats still buffered in the channel are then written and the request
+// completed, bounded by FinalSendTimeout, before the error channel is closed
+func (c *Client) StreamHeartbeats(ctx context.Context) (chan<- Heartbeat, <-chan error) {
+	s := &heartbeatStream{
+		client: c,
+		in:     make(chan Heartbeat, streamBuffer),
+		errs:   make(chan error, streamBuffer),
+		doer:   c.unboundedDoer(),
+	}
+	if c.configErr != nil {
+		s.report(fmt.Errorf("invalid client configuration: %w", c.configErr))
+		close(s.errs)
+		return s.in, s.errs
+	}
+	ctx, done, err := c.trackAsync(ctx)
+	if err != nil {
+		s.report(err)
+		close(s.errs)
+		return s.in, s.errs
+	}
+	if c.disabled() {
+		go func() {
+			defer done()
+			s.discard(ctx)
+		}()
+		return s.in, s.errs
+	}
+	go func() {
+		defer done()
+		s.run(ctx)
+	}()
+	return s.in, s.errs
+}
+
+// heartbeatStream is the state of one StreamHeartbeats call, owned by its run goroutine
+type heartbeatStream struct {
+	client *Client
+	in     chan Heartbeat
+	errs   chan error
+	doer   Doer
+	conn   *streamConn
+}
+
+// streamConn is one open stream request
+type streamConn struct {
+	body   *io.PipeWriter
+	cancel context.CancelFunc
+	// done is closed once the request has returned, after err is set
+	done chan struct{}
+	// err is the request's outcome, nil for a 2xx response
+	err error
+}
+
+// run writes heartbeats until the stream ends, reconnecting after failures
+func (s *heartbeatStream) run(ctx context.Context) {
+	defer close(s.errs)
+	retry := s.client.Retry.withDefaults()
+	clock := s.client.clock()
+
+	var pending []byte
+	failures := 0
+	for {
+		var err error
+		if pending == nil {
+			// Watch the open request so a response, which means the server
+			// has stopped reading, drops the connection right away rather
+			// than on the next write
+			var connDone <-chan struct{}
+			if s.conn != nil {
+				connDone = s.conn.done
+			}
+			select {
+			case <-ctx.Done():
+				s.finish(ctx, nil)
+				return
+			case <-connDone:
+				err = s.lost()
+			case h, ok := <-s.in:
+				if !ok {
+					s.finish(ctx, nil)
+					return
+				}
+				if pending, ok = s.encode(ctx, h); !ok {
+					continue
+				}
+				err = s.write(ctx, pending)
+			}
+		} else {
+			err = s.write(ctx, pending)
+		}
+		if err == nil {
+			pending, failures = nil, 0
+			continue
+		}
+		s.report(err)
+		failures++
+		if sleepContext(ctx, clock, retry.backoff(failures)) != nil {
+			s.finish(ctx, pending)
+			return
+		}
+	}
+}
+
+// encode validates h and renders it as one record of the stream
+func (s *heartbeatStream) encode(ctx context.Context, h Heartbeat) ([]byte, bool) {
+	if err := s.client.validate(h); err != nil {
+		s.report(fmt.Errorf("invalid heartbeat: %w", err))
+		return nil, false
+	}
+	line, err := s.client.marshal(h)
+	if err != nil {
+		s.report(fmt.Errorf("failed to encode heartbeat: %w", err))
+		return nil, false
+	}
+	if err := s.client.validateBody(ctx, s.client.baseURL(nil), line); err != nil {
+		s.report(err)
+		return nil, false
+	}
+	return s.client.encodeStreamRecord(line), true
+}
+
+// write sends one line, connecting first if needed. A failed write drops the
+// connection and returns why it was lost
+func (s *heartbeatStream) write(ctx context.Context, line []byte) error {
+	if s.conn == nil {
+		if err := s.connect(context.WithoutCancel(ctx)); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-s.conn.done:
+		return s.lost()
+	default:
+	}
+	if _, err := s.conn.body.Write(line); err != nil {
+		return s.lost()
+	}
+	return nil
+}
+
+// connect opens a stream request whose lifetime is bounded by ctx
+func (s *heartbeatStream) connect(ctx context.Context) error {
+	c := s.client
+	rc := newRequestConfig(nil)
+	url := fmt.Sprintf("%s/heartbeat/stream", c.baseURL(rc))
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	req, err := c.newBodyRequest(ctx, http.MethodPost, url, pr, c.streamContentType(), rc)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	conn := &streamConn{body: pw, cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(conn.done)
+		resp, err := s.doer.Do(req)
+		if err != nil {
+			conn.err = newTransportError(req.Method, url, err)
+		} else {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+			resp.Body.Close()
+			if !s.client.isSuccess(resp.StatusCode) {
+				conn.err = newStatusError(resp.StatusCode, body)
+			}
+		}
+		// Unblock any write in progress now the request is over
+		pr.CloseWithError(conn.err)
+	}()
+	s.conn = conn
+	return nil
+}
+
+// lost tears down a connection that stopped accepting writes and returns its outcome
+func (s *heartbeatStream) lost() error {
+	conn := s.conn
+	s.conn = nil
+	conn.cancel()
+	<-conn.done
+	if conn.err == nil {
+		return errStreamEnded
+	}
+	return conn.err
+}
+
+// finish writes pending and any heartbeats still buffered, then completes
+// the request and reports the server's verdict, within FinalSendTimeout
+func (s *heartbeatStream) finish(ctx context.Context, pending []byte) {
+	var lines [][]byte
+	if pending != nil {
+		lines = append(lines, pending)
+	}
+	for drained := false; !drained; {
+		select {
+		case h, ok := <-s.in:
+			if !ok {
+				drained = true
+				break
+			}
+			if line, ok := s.encode(ctx, h); ok {
+				lines = append(lines, line)
+			}
+		default:
+			drained = true
+		}
+	}
+	if len(lines) == 0 && s.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), FinalSendTimeout)
+	defer cancel()
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			s.report(err)
+			return
+		}
+	}
+	conn := s.conn
+	s.conn = nil
+	stop := context.AfterFunc(ctx, conn.cancel)
+	defer stop()
+	defer conn.cancel()
+	var writeErr error
+	for _, line := range lines {
+		if _, writeErr = conn.body.Write(line); writeErr != nil {
+			break
+		}
+	}
+	conn.body.Close()
+	<-conn.done
+	switch {
+	case conn.err != nil:
+		s.report(conn.err)
+	case writeErr != nil:
+		s.report(errStreamEnded)
+	}
+}
+
+// report delivers err on the error channel unless it is full
+func (s *heartbeatStream) report(err error) {
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,97 @@
+package medic
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamHeartbeats(t *testing.T) {
+	received := make(chan Heartbeat, 100)
+	var conns int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/heartbeat/stream" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("request = %s %s, want the ndjson stream endpoint", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		conn := atomic.AddInt32(&conns, 1)
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var h Heartbeat
+			if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+				t.Errorf("stream line %q is not a heartbeat: %v", scanner.Text(), err)
+			}
+			received <- h
+			// Reply to the first connection after one heartbeat without
+			// draining the rest of its body
+			if conn == 1 {
+				w.Header().Set("Connection", "close")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(newFakeClock()))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	beats, errs := c.StreamHeartbeats(ctx)
+
+	nextBeat := func() Heartbeat {
+		select {
+		case h := <-received:
+			return h
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not receive a heartbeat")
+			return Heartbeat{}
+		}
+	}
+	nextErr := func() error {
+		select {
+		case err := <-errs:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream reported no error")
+			return nil
+		}
+	}
+
+	beats <- Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	if got := nextBeat(); got.Status != StatusUp {
+		t.Errorf("first heartbeat status = %q, want %q", got.Status, StatusUp)
+	}
+	// The response is noticed without another write
+	var se *StatusError
+	if err := nextErr(); !errors.As(err, &se) || se.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("stream error = %v, want the 503 that dropped the connection", err)
+	}
+
+	beats <- Heartbeat{Status: StatusUp}
+	var ve *ValidationError
+	if err := nextErr(); !errors.As(err, &ve) {
+		t.Errorf("stream error = %v, want *ValidationError", err)
+	}
+
+	// The next beats reconnect
+	for _, status := range []Status{StatusDegraded, StatusDown} {
+		beats <- Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: status}
+		if got := nextBeat(); got.Status != status {
+			t.Errorf("streamed status = %q, want %q", got.Status, status)
+		}
+	}
+
+	cancel()
+	for err := range errs {
+		t.Errorf("unexpected stream error after cancel = %v", err)
+	}
+	if n := atomic.LoadInt32(&conns); n != 2 {
+		t.Errorf("server saw %d connections, want 2", n)
+	}
+}
@@ -0,0 +1,29 @@
+package medic
+
+import "net/http"
+
+// WithSuccessPredicate decides which response status codes count as a
+// successful request, for servers that answer outside plain 2xx. Any other
+// status is returned as a *StatusError. While a predicate is set, redirects
+// are not followed, so fn sees the 3xx itself and a POST is never re-sent as
+// a GET to the Location; an HTTPClient with its own CheckRedirect keeps it.
+// A nil fn restores the default, which accepts every status below 300 and
+// follows redirects
+func WithSuccessPredicate(fn func(statusCode int) bool) Option {
+	return func(c *Client) {
+		c.SuccessPredicate = fn
+	}
+}
+
+// isSuccess applies the client's success predicate to statusCode
+func (c *Client) isSuccess(statusCode int) bool {
+	if c.SuccessPredicate != nil {
+		return c.SuccessPredicate(statusCode)
+	}
+	return statusCode < 300
+}
+
+// useLastResponse is a CheckRedirect that returns each redirect unfollowed
+func useLastResponse(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
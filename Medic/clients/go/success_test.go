@@ -0,0 +1,68 @@
+package medic
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestWithSuccessPredicate(t *testing.T) {
+	only2xxOr3xx := func(code int) bool { return code >= 200 && code < 400 }
+	onlyNoContent := func(code int) bool { return code == http.StatusNoContent }
+	tests := []struct {
+		name         string
+		code         int
+		predicate    func(int) bool
+		wantCode     int
+		wantRequests []string
+	}{
+		{name: "testing default accepts 2xx", code: http.StatusAccepted, wantRequests: []string{"POST /heartbeat"}},
+		{name: "testing default follows 3xx", code: http.StatusFound, wantRequests: []string{"POST /heartbeat", "GET /moved"}},
+		{name: "testing predicate accepts 3xx", code: http.StatusFound, predicate: only2xxOr3xx, wantRequests: []string{"POST /heartbeat"}},
+		{name: "testing predicate rejects 3xx", code: http.StatusFound, predicate: onlyNoContent, wantCode: http.StatusFound, wantRequests: []string{"POST /heartbeat"}},
+		{name: "testing predicate accepts 204", code: http.StatusNoContent, predicate: onlyNoContent, wantRequests: []string{"POST /heartbeat"}},
+		{name: "testing predicate rejects 202", code: http.StatusAccepted, predicate: onlyNoContent, wantCode: http.StatusAccepted, wantRequests: []string{"POST /heartbeat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				switch {
+				case r.URL.Path == "/moved":
+					w.WriteHeader(http.StatusOK)
+				case tt.code >= 300 && tt.code < 400:
+					http.Redirect(w, r, "/moved", tt.code)
+				default:
+					w.WriteHeader(tt.code)
+				}
+			}))
+			defer srv.Close()
+			c := NewClientWithOptions(
+				WithBaseURL(srv.URL),
+				WithRetry(RetryConfig{MaxAttempts: 1}),
+				WithSuccessPredicate(tt.predicate),
+			)
+			err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+			var se *StatusError
+			switch {
+			case tt.wantCode == 0 && err != nil:
+				t.Errorf("SendHeartbeat() unexpected error = %v", err)
+			case tt.wantCode != 0 && (!errors.As(err, &se) || se.StatusCode != tt.wantCode):
+				t.Errorf("SendHeartbeat() error = %v, want a *StatusError with code %d", err, tt.wantCode)
+			}
+			wantSuccesses := uint64(0)
+			if tt.wantCode == 0 {
+				wantSuccesses = 1
+			}
+			if !slices.Equal(requests, tt.wantRequests) {
+				t.Errorf("requests = %q, want %q", requests, tt.wantRequests)
+			}
+			if got := c.Stats().Successes; got != wantSuccesses {
+				t.Errorf("Stats().Successes = %d, want %d", got, wantSuccesses)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package medic
+
+import (
+	"context"
+	"time"
+)
+
+// SendHeartbeatTimed sends a heartbeat post with the default client and
+// returns how long it took. See Client.SendHeartbeatTimed
+func SendHeartbeatTimed(ctx context.Context, h Heartbeat, opts ...RequestOption) (time.Duration, error) {
+	return defaultClient().SendHeartbeatTimed(ctx, h, opts...)
+}
+
+// SendHeartbeatTimed sends a heartbeat post like SendHeartbeatContext and
+// returns the wall-clock time the send took, failed or not: every attempt,
+// the backoff between them, rate limiting, and any fallback servers, as the
+// caller experienced it. Time is read from the client's Clock. Use
+// TimingMetrics for a per-attempt breakdown
+func (c *Client) SendHeartbeatTimed(ctx context.Context, h Heartbeat, opts ...RequestOption) (time.Duration, error) {
+	clock := c.clock()
+	start := clock.Now()
+	_, err := c.sendHeartbeat(ctx, h, opts)
+	return clock.Now().Sub(start), err
+}
@@ -0,0 +1,62 @@
+package medic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendHeartbeatTimed(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   []int
+		wantErr bool
+	}{
+		{name: "testing single attempt", codes: []int{201}},
+		{name: "testing includes retry backoff", codes: []int{503, 503, 201}},
+		{name: "testing failed send", codes: []int{503, 503, 503}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.codes[n.Add(1)-1])
+			}))
+			defer srv.Close()
+			clock := newFakeClock()
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock), WithRetry(RetryConfig{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 4 * time.Second}))
+
+			got, err := c.SendHeartbeatTimed(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendHeartbeatTimed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			// The fake clock only moves while backing off
+			var want time.Duration
+			for _, d := range clock.Sleeps() {
+				want += d
+			}
+			if got != want {
+				t.Errorf("SendHeartbeatTimed() = %v, want %v", got, want)
+			}
+			if len(tt.codes) > 1 && got == 0 {
+				t.Error("SendHeartbeatTimed() = 0, want the retry backoff included")
+			}
+		})
+	}
+
+	t.Run("testing real clock", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(20 * time.Millisecond)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer srv.Close()
+		c := NewClientWithOptions(WithBaseURL(srv.URL))
+		got, err := c.SendHeartbeatTimed(context.Background(), Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp})
+		if err != nil || got < 20*time.Millisecond {
+			t.Errorf("SendHeartbeatTimed() = %v, %v, want at least 20ms and no error", got, err)
+		}
+	})
+}
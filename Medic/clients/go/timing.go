@@ -0,0 +1,116 @@
+package medic
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestTiming breaks down where one request attempt spent its time. Phases
+// that did not happen, such as DNS and connecting on a reused connection,
+// are zero
+type RequestTiming struct {
+	// ConnReused reports whether the attempt reused a pooled connection
+	ConnReused bool
+	// DNS is the time spent resolving the host
+	DNS time.Duration
+	// Connect is the time spent opening the TCP connection
+	Connect time.Duration
+	// TLSHandshake is the time spent on the TLS handshake
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the time from sending the request until the first
+	// byte of the response arrived, so it spans the phases above plus
+	// server processing
+	TimeToFirstByte time.Duration
+	// Total is the time until the response was handled, including reading
+	// the body, or until the attempt failed
+	Total time.Duration
+}
+
+// TimingMetrics is a Metrics that also receives a RequestTiming for every
+// attempt. The client only traces connection phases when its Metrics
+// implements TimingMetrics, so plain metrics pay nothing for it
+type TimingMetrics interface {
+	Metrics
+	// ObserveTiming records the phases of one attempt. statusCode is 0 when
+	// no response was received
+	ObserveTiming(timing RequestTiming, statusCode int, err error)
+}
+
+// requestTimer collects the httptrace events of one attempt
+type requestTimer struct {
+	start time.Time
+
+	mu                  sync.Mutex
+	timing              RequestTiming
+	dnsStart, connStart time.Time
+	tlsStart            time.Time
+}
+
+// traceTiming attaches a trace to req that counts connection reuse in the
+// client's Stats and, when the client's Metrics wants one, times each phase.
+// It returns the request to send and the timer, which is nil without
+// TimingMetrics
+func (c *Client) traceTiming(req *http.Request) (*http.Request, *requestTimer) {
+	gotConn := func(info httptrace.GotConnInfo) { c.stats.recordConn(info.Reused) }
+	if _, ok := c.Metrics.(TimingMetrics); !ok {
+		trace := &httptrace.ClientTrace{GotConn: gotConn}
+		return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), nil
+	}
+	t := &requestTimer{start: time.Now()}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			gotConn(info)
+			t.mu.Lock()
+			t.timing.ConnReused = info.Reused
+			t.mu.Unlock()
+		},
+		DNSStart:          func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.since(&t.dnsStart, &t.timing.DNS) },
+		ConnectStart:      func(string, string) { t.mark(&t.connStart) },
+		ConnectDone:       func(string, string, error) { t.since(&t.connStart, &t.timing.Connect) },
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.since(&t.tlsStart, &t.timing.TLSHandshake)
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.timing.TimeToFirstByte = time.Since(t.start)
+			t.mu.Unlock()
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace)), t
+}
+
+// mark records the start of a phase, keeping the earliest start when dual
+// stack dialing runs attempts in parallel
+func (t *requestTimer) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// since records in d the duration of the first phase to finish since start
+func (t *requestTimer) since(start *time.Time, d *time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !start.IsZero() && *d == 0 {
+		*d = time.Since(*start)
+	}
+}
+
+// observeTiming reports the attempt's timing to the client's TimingMetrics
+func (c *Client) observeTiming(t *requestTimer, statusCode int, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	timing := t.timing
+	t.mu.Unlock()
+	timing.Total = time.Since(t.start)
+	c.Metrics.(TimingMetrics).ObserveTiming(timing, statusCode, err)
+}
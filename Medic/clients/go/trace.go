@@ -0,0 +1,48 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Tracer instruments outgoing requests with distributed tracing. The
+// medicotel subpackage provides an OpenTelemetry implementation
+type Tracer interface {
+	// StartSpan begins a span for the named operation and returns the context
+	// to make the request with, plus a function that ends the span
+	StartSpan(ctx context.Context, name string) (context.Context, func(statusCode int, err error))
+	// Inject propagates the trace context from ctx into outgoing request headers
+	Inject(ctx context.Context, header http.Header)
+}
+
+// WithTracer instruments heartbeat requests with the given tracer
+func WithTracer(t Tracer) Option {
+	return func(c *Client) {
+		c.Tracer = t
+	}
+}
+
+// startSpan starts a span when a tracer is configured; the returned end
+// function is always safe to call
+func (c *Client) startSpan(ctx context.Context, name string) (context.Context, func(*response, error)) {
+	if c.Tracer == nil {
+		return ctx, func(*response, error) {}
+	}
+	ctx, end := c.Tracer.StartSpan(ctx, name)
+	return ctx, func(resp *response, err error) {
+		end(statusCode(resp, err), err)
+	}
+}
+
+// statusCode returns the HTTP status code of a completed call, or 0 if no response was received
+func statusCode(resp *response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package medic
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Connection pool defaults, sized for a client that talks to a single Medic host
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 32
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// newTransport returns a transport that honors the HTTP_PROXY, HTTPS_PROXY,
+// and NO_PROXY environment variables, with a connection pool sized for one host
+func newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	t.MaxIdleConns = DefaultMaxIdleConns
+	t.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	t.IdleConnTimeout = DefaultIdleConnTimeout
+	return t
+}
+
+// configureTransport applies fn to a copy of the client's transport, so
+// neither a caller-supplied http.Client nor http.DefaultTransport is
+// modified. Custom RoundTrippers that aren't an *http.Transport are left as is
+func (c *Client) configureTransport(fn func(*http.Transport)) {
+	hc := *c.httpClient()
+	var t *http.Transport
+	switch rt := hc.Transport.(type) {
+	case nil:
+		t = newTransport()
+	case *http.Transport:
+		t = rt.Clone()
+	default:
+		return
+	}
+	fn(t)
+	hc.Transport = t
+	c.HTTPClient = &hc
+}
+
+// WithProxy routes all requests through the given proxy, ignoring the proxy
+// environment variables
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.Proxy = http.ProxyURL(proxy)
+		})
+	}
+}
+
+// WithoutProxy connects directly to Medic, ignoring the proxy environment variables
+func WithoutProxy() Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.Proxy = nil
+		})
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to Medic. The
+// config is cloned, so later changes to cfg have no effect
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.TLSClientConfig = cfg.Clone()
+		})
+	}
+}
+
+// WithRootCAFile trusts the certificates in the PEM bundle at path, in place
+// of the system roots, when verifying the Medic server. Errors loading the
+// bundle are returned from the client's first request
+func WithRootCAFile(path string) Option {
+	return func(c *Client) {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			c.setConfigErr(fmt.Errorf("failed to read root CA file: %w", err))
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			c.setConfigErr(fmt.Errorf("no certificates found in root CA file %s", path))
+			return
+		}
+		c.configureTransport(func(t *http.Transport) {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{}
+			} else {
+				t.TLSClientConfig = t.TLSClientConfig.Clone()
+			}
+			t.TLSClientConfig.RootCAs = pool
+		})
+	}
+}
+
+// WithMaxIdleConns caps the idle connections kept open across all hosts
+func WithMaxIdleConns(n int) Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.MaxIdleConns = n
+		})
+	}
+}
+
+// WithMaxIdleConnsPerHost caps the idle connections kept open to the Medic
+// host. Raise it when many goroutines send concurrently, to avoid reconnecting
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.MaxIdleConnsPerHost = n
+		})
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before closing
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			t.IdleConnTimeout = d
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package medic
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A proxied request carries the absolute target URL
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	c := NewClientWithOptions(WithBaseURL("http://medic.internal.example"), WithProxy(proxyURL))
+	if err := c.SendHeartbeat(h); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if proxied != "http://medic.internal.example/heartbeat" {
+		t.Errorf("proxy saw %q, want http://medic.internal.example/heartbeat", proxied)
+	}
+}
+
+func TestWithoutProxy(t *testing.T) {
+	hit := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	hc := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(&url.URL{Scheme: "http", Host: "127.0.0.1:1"})}}
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithHTTPClient(hc), WithoutProxy())
+	if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+		t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if !hit {
+		t.Error("request did not reach the server directly")
+	}
+	if hc.Transport.(*http.Transport).Proxy == nil {
+		t.Error("WithoutProxy modified the caller's transport")
+	}
+}
+
+func TestWithRootCAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	// Without the private CA the certificate is rejected
+	untrusted := NewClientWithOptions(WithBaseURL(srv.URL), WithRetry(RetryConfig{MaxAttempts: 1}))
+	if err := untrusted.SendHeartbeat(h); err == nil {
+		t.Error("SendHeartbeat() succeeded against an untrusted certificate")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, pemBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	trusted := NewClientWithOptions(WithBaseURL(srv.URL), WithRootCAFile(caFile))
+	if err := trusted.SendHeartbeat(h); err != nil {
+		t.Errorf("SendHeartbeat() with root CA unexpected error = %v", err)
+	}
+
+	missing := NewClientWithOptions(WithBaseURL(srv.URL), WithRootCAFile(filepath.Join(t.TempDir(), "missing.pem")))
+	if err := missing.SendHeartbeat(h); err == nil || !strings.Contains(err.Error(), "root CA file") {
+		t.Errorf("SendHeartbeat() error = %v, want root CA file error", err)
+	}
+}
+
+func TestConnectionPoolOptions(t *testing.T) {
+	c := NewClient("")
+	tr := c.HTTPClient.Transport.(*http.Transport)
+	if tr.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost {
+		t.Errorf("default MaxIdleConnsPerHost = %d, want %d", tr.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost)
+	}
+
+	c = NewClientWithOptions(WithMaxIdleConns(10), WithMaxIdleConnsPerHost(5), WithIdleConnTimeout(time.Second))
+	tr = c.HTTPClient.Transport.(*http.Transport)
+	if tr.MaxIdleConns != 10 || tr.MaxIdleConnsPerHost != 5 || tr.IdleConnTimeout != time.Second {
+		t.Errorf("transport pool = %d/%d/%v, want 10/5/1s", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+	if http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost == 5 {
+		t.Error("pool options modified http.DefaultTransport")
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "medic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "medic.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	var paths []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	srv.Listener.Close()
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "testing WithUnixSocket", opts: []Option{WithUnixSocket(sock)}},
+		{name: "testing unix base URL", opts: []Option{WithBaseURL("unix://" + sock)}},
+		{name: "testing unix socket with base path", opts: []Option{WithUnixSocket(sock), WithBasePath("/agent")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths = nil
+			c, err := NewClientStrict("", tt.opts...)
+			if err != nil {
+				t.Fatalf("NewClientStrict() unexpected error = %v", err)
+			}
+			if err := c.SendHeartbeat(Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}); err != nil {
+				t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+			}
+			want := normalizeBasePath(c.BasePath) + "/heartbeat"
+			if len(paths) != 1 || paths[0] != want {
+				t.Errorf("paths = %v, want [%s]", paths, want)
+			}
+		})
+	}
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	h := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+
+	var buf bytes.Buffer
+	c := NewClientWithOptions(WithBaseURL(srv.URL), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))), WithInsecureSkipVerify())
+	err := c.SendHeartbeat(h)
+	if !insecureAllowed {
+		if !errors.Is(err, errInsecureBlocked) {
+			t.Errorf("SendHeartbeat() error = %v, want errInsecureBlocked", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("SendHeartbeat() unexpected error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "TLS certificate verification is disabled") {
+		t.Errorf("construction log = %q, want insecure warning", buf.String())
+	}
+}
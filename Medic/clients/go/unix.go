@@ -0,0 +1,41 @@
+package medic
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// unixSocketHost is the placeholder host used in request URLs when sending
+// over a Unix socket, where the host does not pick the connection
+const unixSocketHost = "http://localhost"
+
+// WithUnixSocket sends requests over the Unix domain socket at path, such as
+// a local Medic agent's, using plain HTTP over the socket. Request paths are
+// unchanged. If no base URL is set, requests use http://localhost as the host
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		c.configureTransport(func(t *http.Transport) {
+			var d net.Dialer
+			t.Proxy = nil
+			t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", path)
+			}
+		})
+		if c.BaseURL == "" {
+			c.BaseURL = unixSocketHost
+		}
+	}
+}
+
+// applyUnixBaseURL turns a unix:// base URL, such as unix:///var/run/medic.sock,
+// into WithUnixSocket for the socket at its path
+func (c *Client) applyUnixBaseURL() {
+	path, ok := strings.CutPrefix(strings.TrimSpace(c.BaseURL), "unix://")
+	if !ok {
+		return
+	}
+	c.BaseURL = ""
+	WithUnixSocket(path)(c)
+}
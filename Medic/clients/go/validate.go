@@ -0,0 +1,176 @@
+package medic
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultNamePattern is the pattern heartbeat and service names must match
+// unless a client overrides it with WithNamePattern
+var DefaultNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateHeartbeat checks every field of h against its validate struct
+// tags, matching names against DefaultNamePattern. It does no I/O and needs
+// no client, so heartbeats loaded from config can be rejected at startup. The
+// send path applies the same rules
+func ValidateHeartbeat(h Heartbeat) error {
+	return validateHeartbeat(h, rules{namePattern: DefaultNamePattern})
+}
+
+// Validate is shorthand for ValidateHeartbeat(h)
+func (h Heartbeat) Validate() error {
+	return ValidateHeartbeat(h)
+}
+
+// rules are the settings validation checks fields against
+type rules struct {
+	// namePattern is the pattern fields tagged "name" must match
+	namePattern *regexp.Regexp
+	// metadata bounds fields tagged "keys"; zero fields use the defaults
+	metadata MetadataLimits
+}
+
+// validateHeartbeat holds the heartbeat rules shared by ValidateHeartbeat
+// and every client send
+func validateHeartbeat(h Heartbeat, r rules) error {
+	return validateStruct(h, r)
+}
+
+// WithNamePattern overrides DefaultNamePattern for heartbeats sent by the
+// client, for deployments with looser naming conventions
+func WithNamePattern(pattern *regexp.Regexp) Option {
+	return func(c *Client) {
+		c.NamePattern = pattern
+	}
+}
+
+// validate checks h using the client's name pattern and metadata limits
+func (c *Client) validate(h Heartbeat) error {
+	pattern := c.NamePattern
+	if pattern == nil {
+		pattern = DefaultNamePattern
+	}
+	return validateHeartbeat(h, rules{namePattern: pattern, metadata: c.MetadataLimits})
+}
+
+// enum is implemented by field types restricted to a fixed set of values
+type enum interface {
+	valid() bool
+	allowed() []string
+}
+
+// validateStruct walks the exported fields of v and enforces their validate
+// tags, collecting every violation into one *ValidationError. Each field
+// reports at most its first failing rule, checked against r
+func validateStruct(v interface{}, r rules) error {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+	var violations []FieldError
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if msg := checkRule(strings.TrimSpace(rule), rv.Field(i), r); msg != "" {
+				violations = append(violations, FieldError{Field: fieldName(field), Message: msg})
+				break
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return newValidationError(violations)
+	}
+	return nil
+}
+
+// checkRule applies one validate rule to f, returning the violation message
+// or "" if f passes
+func checkRule(rule string, f reflect.Value, r rules) string {
+	switch rule {
+	case "required":
+		if f.IsZero() {
+			return "is required"
+		}
+	case "enum":
+		// Unset values are left to the required rule
+		e, ok := f.Interface().(enum)
+		if ok && !f.IsZero() && !e.valid() {
+			return fmt.Sprintf("%q is not one of %s", f.Interface(), strings.Join(e.allowed(), ", "))
+		}
+	case "name":
+		// Unset values are left to the required rule
+		if s := f.String(); s != "" && !r.namePattern.MatchString(s) {
+			return fmt.Sprintf("%q does not match %s", s, r.namePattern)
+		}
+	case "positive":
+		// Zero means unset
+		if f.CanInt() && f.Int() < 0 {
+			return "must be positive"
+		}
+	case "names":
+		return validateNames(f, r.namePattern)
+	case "keys":
+		return validateKeys(f, r.metadata.withDefaults())
+	}
+	return ""
+}
+
+// validateNames checks that every entry of a string slice is non-empty and
+// matches namePattern, returning the violation message
+func validateNames(f reflect.Value, namePattern *regexp.Regexp) string {
+	if f.Kind() != reflect.Slice || f.Type().Elem().Kind() != reflect.String {
+		return ""
+	}
+	for i := 0; i < f.Len(); i++ {
+		switch s := f.Index(i).String(); {
+		case s == "":
+			return fmt.Sprintf("entry %d is empty", i)
+		case !namePattern.MatchString(s):
+			return fmt.Sprintf("%q does not match %s", s, namePattern)
+		}
+	}
+	return ""
+}
+
+// validateKeys checks a string-keyed map against limits: its number of
+// entries, and that every key is non-empty and no key or string value is too
+// long. It returns the violation message, naming the offending key
+func validateKeys(m reflect.Value, limits MetadataLimits) string {
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return ""
+	}
+	if m.Len() > limits.MaxEntries {
+		return fmt.Sprintf("has %d entries, more than %d", m.Len(), limits.MaxEntries)
+	}
+	keys := make([]string, 0, m.Len())
+	for _, k := range m.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	// Report the same key on every run
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch {
+		case k == "":
+			return "has an empty key"
+		case len(k) > limits.MaxKeyLength:
+			return fmt.Sprintf("key %q is longer than %d characters", k, limits.MaxKeyLength)
+		}
+		if v := m.MapIndex(reflect.ValueOf(k).Convert(m.Type().Key())); v.Kind() == reflect.String && v.Len() > limits.MaxValueLength {
+			return fmt.Sprintf("value of key %q is %d bytes, longer than %d", k, v.Len(), limits.MaxValueLength)
+		}
+	}
+	return ""
+}
+
+// fieldName returns the JSON name of a struct field, falling back to the Go name
+func fieldName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
@@ -0,0 +1,226 @@
+package medic
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHeartbeatValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		h       Heartbeat
+		wantErr string
+	}{
+		{
+			name: "testing valid heartbeat",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Service:       "fakeservice",
+				Status:        "UP",
+			},
+		},
+		{
+			name: "testing no name",
+			h: Heartbeat{
+				Service: "fakeservice",
+				Status:  "UP",
+			},
+			wantErr: "heartbeat_name is required",
+		},
+		{
+			name: "testing invalid status",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Status:        "Up",
+			},
+			wantErr: `status "Up" is not one of UP, DOWN, DEGRADED`,
+		},
+		{
+			name: "testing valid severity",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Status:        StatusDown,
+				Severity:      SeverityWarning,
+			},
+		},
+		{
+			name: "testing invalid severity",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Severity:      "page-me",
+			},
+			wantErr: `severity "page-me" is not one of CRITICAL, WARNING, INFO`,
+		},
+		{
+			name: "testing name with spaces",
+			h: Heartbeat{
+				HeartbeatName: "Dont call me shirley",
+			},
+			wantErr: `heartbeat_name "Dont call me shirley" does not match ^[a-zA-Z0-9_-]+$`,
+		},
+		{
+			name: "testing service with slash",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Service:       "fake/service",
+			},
+			wantErr: `service_name "fake/service" does not match ^[a-zA-Z0-9_-]+$`,
+		},
+		{
+			name: "testing negative interval",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Interval:      -time.Second,
+			},
+			wantErr: "interval_seconds must be positive",
+		},
+		{
+			name: "testing valid metadata",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Metadata:      map[string]string{"region": "us-east-1", "version": ""},
+			},
+		},
+		{
+			name: "testing empty metadata key",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Metadata:      map[string]string{"": "us-east-1"},
+			},
+			wantErr: "metadata has an empty key",
+		},
+		{
+			name: "testing long metadata key",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Metadata:      map[string]string{strings.Repeat("k", 65): "v"},
+			},
+			wantErr: `metadata key "` + strings.Repeat("k", 65) + `" is longer than 64 characters`,
+		},
+		{
+			name: "testing multiple services",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Services:      []string{"billing", "invoices"},
+			},
+		},
+		{
+			name: "testing empty service in list",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Services:      []string{"billing", ""},
+			},
+			wantErr: "service_names entry 1 is empty",
+		},
+		{
+			name: "testing only empty services",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Services:      []string{""},
+			},
+			wantErr: "service_names entry 0 is empty",
+		},
+		{
+			name: "testing invalid service in list",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Services:      []string{"billing", "in voices"},
+			},
+			wantErr: `service_names "in voices" does not match ^[a-zA-Z0-9_-]+$`,
+		},
+		{
+			name: "testing long metadata value",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Metadata:      map[string]string{"region": "us-east-1", "blob": strings.Repeat("v", 1025)},
+			},
+			wantErr: `metadata value of key "blob" is 1025 bytes, longer than 1024`,
+		},
+		{
+			name: "testing too many metadata entries",
+			h: Heartbeat{
+				HeartbeatName: "staging-fake-heartbeat-hb",
+				Metadata:      metadataEntries(33),
+			},
+			wantErr: "metadata has 33 entries, more than 32",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.h.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithNamePattern(t *testing.T) {
+	c := NewClientWithOptions(WithNamePattern(regexp.MustCompile(`^[a-z0-9.-]+$`)))
+	if err := c.validate(Heartbeat{HeartbeatName: "staging.fake-heartbeat"}); err != nil {
+		t.Errorf("validate() unexpected error = %v", err)
+	}
+	if err := c.validate(Heartbeat{HeartbeatName: "Staging_Heartbeat"}); err == nil {
+		t.Error("validate() expected an error for a name outside the custom pattern")
+	}
+}
+
+func TestValidateAggregatesFields(t *testing.T) {
+	h := Heartbeat{
+		Status:   "Up",
+		Metadata: map[string]string{"": "us-east-1"},
+		Interval: -time.Second,
+	}
+	err := h.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate() error = %v, want *ValidationError", err)
+	}
+	want := []FieldError{
+		{Field: "heartbeat_name", Message: "is required"},
+		{Field: "status", Message: `"Up" is not one of UP, DOWN, DEGRADED`},
+		{Field: "metadata", Message: "has an empty key"},
+		{Field: "interval_seconds", Message: "must be positive"},
+	}
+	if !reflect.DeepEqual(ve.Fields, want) {
+		t.Errorf("Fields = %+v, want %+v", ve.Fields, want)
+	}
+	if ve.Field != "heartbeat_name" || ve.Message != "is required" {
+		t.Errorf("Field, Message = %q, %q, want the first violation", ve.Field, ve.Message)
+	}
+	wantMsg := `heartbeat_name is required; status "Up" is not one of UP, DOWN, DEGRADED; metadata has an empty key; interval_seconds must be positive`
+	if err.Error() != wantMsg {
+		t.Errorf("Error() = %q, want %q", err.Error(), wantMsg)
+	}
+}
+
+func TestValidateHeartbeatMatchesSendPath(t *testing.T) {
+	tests := []struct {
+		name string
+		h    Heartbeat
+	}{
+		{name: "testing valid heartbeat", h: Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}},
+		{name: "testing invalid name", h: Heartbeat{HeartbeatName: "staging fake", Status: StatusUp}},
+		{name: "testing missing fields", h: Heartbeat{}},
+	}
+	c := NewClientWithOptions(WithBaseURL("https://medic.example.com"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := c.validate(tt.h)
+			got := ValidateHeartbeat(tt.h)
+			if (got == nil) != (want == nil) || (got != nil && got.Error() != want.Error()) {
+				t.Errorf("ValidateHeartbeat() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package medic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// BodyValidator checks each JSON heartbeat body before it is sent, such as
+// medicschema's check against the schema the server publishes
+type BodyValidator interface {
+	// ValidateBody returns an error to refuse body, a single heartbeat as it
+	// will be sent to target. Nothing is sent when it fails
+	ValidateBody(ctx context.Context, target BodyTarget, body []byte) error
+}
+
+// WithBodyValidator checks every JSON heartbeat body, single, batch or
+// streamed, with v before sending it. A batch is checked one heartbeat at a
+// time. Protobuf bodies and dry runs are not checked
+func WithBodyValidator(v BodyValidator) Option {
+	return func(c *Client) {
+		c.BodyValidator = v
+	}
+}
+
+// BodyTarget is the Medic server a BodyValidator's body is bound for, with
+// the client that sends it
+type BodyTarget struct {
+	// BaseURL is the server's base URL, including any base path
+	BaseURL string
+
+	client *Client
+}
+
+// Get fetches path from the server with the client's authentication,
+// headers and retries, and returns the body of a 2xx response
+func (t BodyTarget) Get(ctx context.Context, path string) ([]byte, error) {
+	resp, err := t.client.doWithRetry(ctx, http.MethodGet, t.BaseURL+path, nil, strings.TrimPrefix(path, "/"), newRequestConfig(nil))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Now reads the client's clock
+func (t BodyTarget) Now() time.Time {
+	return t.client.clock().Now()
+}
+
+// Logger returns the client's logger, or slog.Default when it has none
+func (t BodyTarget) Logger() *slog.Logger {
+	if t.client.Logger == nil {
+		return slog.Default()
+	}
+	return t.client.Logger
+}
+
+// validatesBodies reports whether bodies are checked before sending
+func (c *Client) validatesBodies() bool {
+	return c.BodyValidator != nil && !c.DryRun && c.Encoding == EncodingJSON
+}
+
+// validateBody checks body, a JSON heartbeat bound for base, with the
+// client's BodyValidator
+func (c *Client) validateBody(ctx context.Context, base string, body []byte) error {
+	if !c.validatesBodies() {
+		return nil
+	}
+	return c.BodyValidator.ValidateBody(ctx, BodyTarget{BaseURL: base, client: c}, body)
+}
+
+// validateBatchBody checks each heartbeat of a JSON batch body, returning
+// the errors by batch index
+func (c *Client) validateBatchBody(ctx context.Context, base string, body []byte) map[int]error {
+	if !c.validatesBodies() {
+		return nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return map[int]error{0: fmt.Errorf("batch body is not a JSON array: %w", err)}
+	}
+	invalid := map[int]error{}
+	for i, item := range items {
+		if err := c.validateBody(ctx, base, item); err != nil {
+			invalid[i] = err
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	return invalid
+}
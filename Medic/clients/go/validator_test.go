@@ -0,0 +1,111 @@
+package medic
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errDegradedRefused = errors.New("degraded refused")
+
+// degradedValidator refuses DEGRADED bodies and records the target it saw
+type degradedValidator struct {
+	target BodyTarget
+}
+
+func (v *degradedValidator) ValidateBody(ctx context.Context, target BodyTarget, body []byte) error {
+	v.target = target
+	if bytes.Contains(body, []byte(`"DEGRADED"`)) {
+		return errDegradedRefused
+	}
+	return nil
+}
+
+func TestWithBodyValidator(t *testing.T) {
+	up := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusUp}
+	degraded := Heartbeat{HeartbeatName: "staging-fake-heartbeat-hb", Status: StatusDegraded}
+	var posts atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"published": true}`))
+			return
+		}
+		posts.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		send func(c *Client) error
+	}{
+		{name: "testing single", send: func(c *Client) error { return c.SendHeartbeat(degraded) }},
+		{name: "testing batch", send: func(c *Client) error {
+			err := c.SendHeartbeats([]Heartbeat{up, degraded})
+			var be *BatchError
+			if errors.As(err, &be) && len(be.Invalid) == 1 {
+				return be.Invalid[1]
+			}
+			return err
+		}},
+		{name: "testing raw", send: func(c *Client) error {
+			resp, err := c.Do(context.Background(), degraded)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return err
+		}},
+		{name: "testing stream", send: func(c *Client) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			beats, errs := c.StreamHeartbeats(ctx)
+			beats <- degraded
+			select {
+			case err := <-errs:
+				return err
+			case <-time.After(5 * time.Second):
+				return nil
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts.Store(0)
+			v := &degradedValidator{}
+			c := NewClientWithOptions(WithBaseURL(srv.URL), WithBasePath("/api"), WithBodyValidator(v))
+			if err := tt.send(c); !errors.Is(err, errDegradedRefused) {
+				t.Errorf("send error = %v, want the validator's error", err)
+			}
+			if posts.Load() != 0 {
+				t.Errorf("server got %d posts, want none", posts.Load())
+			}
+			if v.target.BaseURL != srv.URL+"/api" {
+				t.Errorf("target BaseURL = %q, want %q", v.target.BaseURL, srv.URL+"/api")
+			}
+		})
+	}
+
+	t.Run("testing target fetches from the server", func(t *testing.T) {
+		v := &degradedValidator{}
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithBodyValidator(v))
+		if err := c.SendHeartbeat(up); err != nil {
+			t.Fatalf("SendHeartbeat() unexpected error = %v", err)
+		}
+		body, err := v.target.Get(context.Background(), "/schema")
+		if err != nil || string(body) != `{"published": true}` {
+			t.Errorf("Get() = %q, %v, want the served document", body, err)
+		}
+	})
+
+	t.Run("testing dry run is not checked", func(t *testing.T) {
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithDryRun(), WithSilentLogging(), WithBodyValidator(&degradedValidator{}))
+		if err := c.SendHeartbeat(degraded); err != nil {
+			t.Errorf("SendHeartbeat() unexpected error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,7 @@
+package medic
+
+// Version is the version of this client library
+const Version = "0.2.0"
+
+// DefaultUserAgent identifies this library and version to the Medic server
+const DefaultUserAgent = "medic-go/" + Version
@@ -0,0 +1,97 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval is how often WaitUntilHealthy first polls when given
+// a non-positive interval
+const DefaultPollInterval = time.Second
+
+// MaxPollInterval caps how far WaitUntilHealthy backs off between polls,
+// unless the interval it was given is already longer
+const MaxPollInterval = 30 * time.Second
+
+// UnhealthyError is returned by WaitUntilHealthy when ctx ends before the
+// heartbeat reads UP. errors.Is matches it against the context's error
+type UnhealthyError struct {
+	// Name is the heartbeat that was polled
+	Name string
+	// Status is the last status Medic reported, or "" if no poll succeeded
+	Status Status
+	// LastErr is the error of the last poll, if it failed, such as
+	// ErrHeartbeatNotFound before the heartbeat is first sent
+	LastErr error
+	// Err is the context's error
+	Err error
+}
+
+func (e *UnhealthyError) Error() string {
+	msg := fmt.Sprintf("heartbeat %s did not become healthy", e.Name)
+	if e.Status != "" {
+		msg += fmt.Sprintf(" (last status %s)", e.Status)
+	}
+	if e.LastErr != nil {
+		msg += fmt.Sprintf(" (last error: %v)", e.LastErr)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *UnhealthyError) Unwrap() error {
+	return e.Err
+}
+
+// WaitUntilHealthy polls the named heartbeat with the default client until
+// it reads UP. See Client.WaitUntilHealthy
+func WaitUntilHealthy(ctx context.Context, name string, pollInterval time.Duration) error {
+	return defaultClient().WaitUntilHealthy(ctx, name, pollInterval)
+}
+
+// WaitUntilHealthy blocks until Medic reports the named heartbeat as UP,
+// polling GetHeartbeat first after pollInterval and then backing off,
+// doubling the wait after each unhealthy poll up to MaxPollInterval. A
+// heartbeat that is not found yet, or a lookup that fails on a connection
+// error, 429, or 5xx, is polled again. It returns nil once the heartbeat is
+// UP, an *UnhealthyError with the last observed status when ctx ends first,
+// or the lookup's error when it can't succeed by retrying, such as on a 401
+func (c *Client) WaitUntilHealthy(ctx context.Context, name string, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = DefaultPollInterval
+	}
+	maxDelay := max(pollInterval, MaxPollInterval)
+	clock := c.clock()
+
+	var last Status
+	delay := pollInterval
+	for {
+		h, err := c.GetHeartbeat(ctx, name)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return &UnhealthyError{Name: name, Status: last, LastErr: lastPollErr(err, ctxErr), Err: ctxErr}
+		}
+		switch {
+		case err == nil && h.Status == StatusUp:
+			return nil
+		case err == nil:
+			last = h.Status
+		case !errors.Is(err, ErrHeartbeatNotFound) && !queueRetryable(err):
+			return err
+		}
+
+		if sleepErr := sleepContext(ctx, clock, delay); sleepErr != nil {
+			return &UnhealthyError{Name: name, Status: last, LastErr: err, Err: sleepErr}
+		}
+		delay = min(delay*2, maxDelay)
+	}
+}
+
+// lastPollErr drops a poll error that only reports ctx ending mid-lookup,
+// which UnhealthyError already carries as Err
+func lastPollErr(err, ctxErr error) error {
+	if errors.Is(err, ctxErr) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+package medic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitUntilHealthy(t *testing.T) {
+	tests := []struct {
+		name string
+		// codes and statuses answer successive polls, repeating the last
+		codes     []int
+		statuses  []Status
+		timeout   time.Duration
+		wantPolls int64
+		wantErr   func(error) bool
+		wantLast  Status
+	}{
+		{
+			name:      "testing becomes healthy",
+			codes:     []int{404, 503, 200, 200, 200},
+			statuses:  []Status{"", "", StatusDown, StatusDegraded, StatusUp},
+			wantPolls: 5,
+		},
+		{
+			name:     "testing times out with last status",
+			codes:    []int{200},
+			statuses: []Status{StatusDown},
+			timeout:  60 * time.Millisecond,
+			wantErr:  func(err error) bool { return errors.Is(err, context.DeadlineExceeded) },
+			wantLast: StatusDown,
+		},
+		{
+			name:      "testing permanent error stops polling",
+			codes:     []int{401},
+			statuses:  []Status{""},
+			wantPolls: 1,
+			wantErr:   func(err error) bool { return errors.Is(err, ErrClientError) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := min(int(polls.Add(1))-1, len(tt.codes)-1)
+				w.WriteHeader(tt.codes[i])
+				if tt.codes[i] == http.StatusOK {
+					fmt.Fprintf(w, `{"success":true,"message":"","results":{"heartbeat_name":"staging-fake-heartbeat-hb","status":%q}}`, tt.statuses[i])
+				}
+			}))
+			defer srv.Close()
+
+			opts := []Option{WithBaseURL(srv.URL), WithRetry(RetryConfig{MaxAttempts: 1})}
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			} else {
+				opts = append(opts, WithClock(newFakeClock()))
+			}
+			c := NewClientWithOptions(opts...)
+
+			err := c.WaitUntilHealthy(ctx, "staging-fake-heartbeat-hb", 10*time.Millisecond)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("WaitUntilHealthy() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !tt.wantErr(err) {
+				t.Fatalf("WaitUntilHealthy() error = %v, not the expected error", err)
+			}
+			if tt.wantPolls != 0 && polls.Load() != tt.wantPolls {
+				t.Errorf("WaitUntilHealthy() polled %d times, want %d", polls.Load(), tt.wantPolls)
+			}
+			var ue *UnhealthyError
+			if tt.wantLast != "" && (!errors.As(err, &ue) || ue.Status != tt.wantLast) {
+				t.Errorf("WaitUntilHealthy() error = %v, want an *UnhealthyError with status %s", err, tt.wantLast)
+			}
+		})
+	}
+
+	t.Run("testing backoff schedule", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+		clock := newFakeClock()
+		c := NewClientWithOptions(WithBaseURL(srv.URL), WithClock(clock), WithRetry(RetryConfig{MaxAttempts: 1}))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			for len(clock.Sleeps()) < 6 {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+
+		err := c.WaitUntilHealthy(ctx, "staging-fake-heartbeat-hb", 5*time.Second)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("WaitUntilHealthy() error = %v, want context.Canceled", err)
+		}
+		want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second, 30 * time.Second, 30 * time.Second}
+		if got := clock.Sleeps()[:6]; !reflect.DeepEqual(got, want) {
+			t.Errorf("poll delays = %v, want %v", got, want)
+		}
+		var ue *UnhealthyError
+		if !errors.As(err, &ue) || ue.Status != "" {
+			t.Errorf("WaitUntilHealthy() error = %v, want an *UnhealthyError with no status", err)
+		}
+	})
+}